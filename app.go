@@ -1,18 +1,16 @@
 package docify
 
 import (
-	"github.com/getevo/evo/v2/lib/log"
-	"github.com/getevo/docify/markdown"
-	"github.com/getevo/docify/openapi"
-	"github.com/getevo/docify/postman"
+	"context"
 	"github.com/getevo/evo/v2/lib/application"
 	"github.com/getevo/evo/v2/lib/args"
+	"github.com/getevo/evo/v2/lib/log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var OpenAPI *openapi.OpenAPI
-
 type App struct {
 }
 
@@ -35,11 +33,36 @@ func (a App) WhenReady() error {
 		go func() {
 			time.Sleep(1 * time.Second)
 			log.Info("Docifying ...")
-			SerializeEntities()
-			postman.Generate(&doc)
-			markdown.Generate(&doc)
-
-			os.Exit(1)
+			var opts = Options{}
+			if seed := args.Get("--docify-seed"); seed != "" {
+				v, err := strconv.ParseInt(seed, 10, 64)
+				if err != nil {
+					log.Error("invalid --docify-seed: ", err)
+					os.Exit(1)
+				}
+				opts.Seed = &v
+			}
+			if names := args.Get("--docify-generators"); names != "" {
+				opts.Generators = strings.Split(names, ",")
+			}
+			doc, err := Build(context.Background(), opts)
+			if err != nil {
+				log.Error(err)
+			}
+			if doc == nil {
+				os.Exit(1)
+			}
+			reports, err := Generate(context.Background(), doc, opts)
+			for _, report := range reports {
+				for _, file := range report.Files {
+					log.Info(report.Generator+": ", file)
+				}
+			}
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+			os.Exit(0)
 		}()
 	}
 	return nil
@@ -5,155 +5,302 @@ import (
 	"github.com/getevo/docify/serializer"
 	"go/ast"
 	"go/format"
-	"go/parser"
-	"go/token"
-	"os"
-	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 )
 
+var definitions = map[reflect.Type]*cachedDefinition{}
+var definitionsMu sync.Mutex
+
+type cachedDefinition struct {
+	source     *parsedPackage
+	definition *serializer.StructDefinition
+}
+
 func GetStructDefinition(t reflect.Type) (*serializer.StructDefinition, error) {
 	if t.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("provided type is not a struct")
 	}
 
-	// Get the directory of the package where the type is defined
-	dir := "../" + t.PkgPath()
+	// Find the struct in the parsed package, parsing the package on first use
+	p, spec, err := lookupTypeSpec(t)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+	if cached, ok := definitions[t]; ok && cached.source == p {
+		return cached.definition, nil
+	}
 
-	// Get the name of the struct
-	structName := t.Name()
+	structDef, err := buildStructDefinition(p, spec, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	definitions[t] = &cachedDefinition{source: p, definition: structDef}
+	return structDef, nil
+}
+
+func buildStructDefinition(p *parsedPackage, spec *typeSpec, seen map[string]bool) (*serializer.StructDefinition, error) {
+	st, ok := spec.Spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct", spec.Spec.Name.Name)
+	}
+	structName := spec.Spec.Name.Name
+
+	// Guard against pointer embedding cycles
+	var key = p.Package.ImportPath + "." + structName
+	if seen[key] {
+		return nil, fmt.Errorf("embedding cycle detected at %s", key)
+	}
+	seen[key] = true
+	defer delete(seen, key)
 
 	var structDef serializer.StructDefinition
+	var sb strings.Builder
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	// ✅ Step 1: Extract attached comments for the struct
+	if spec.Doc != nil {
+		for _, comment := range spec.Doc.List {
+			sb.WriteString(comment.Text + "\n")
 		}
+	}
 
-		if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") {
-			fset := token.NewFileSet()
-			node, err := parser.ParseFile(fset, path, nil, parser.AllErrors|parser.ParseComments)
-			if err != nil {
-				return err
-			}
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+
+	// Fields declared directly on the struct shadow promoted ones
+	var declared = map[string]bool{}
+	for _, field := range st.Fields.List {
+		for _, name := range field.Names {
+			declared[name.Name] = true
+		}
+	}
+
+	var prevLine = p.Fset.Position(st.Fields.Opening).Line
 
-			var commentMap = map[int]string{}
+	for _, field := range st.Fields.List {
+		names := []string{}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		// ✅ Step 2: Use getTypeString to resolve all types
+		typeStr := getTypeString(field.Type)
+
+		// ✅ Step 3: Extract struct tag
+		var tag string
+		if field.Tag != nil {
+			tag = strings.Trim(field.Tag.Value, "`")
+		}
+
+		// ✅ Step 4: Extract doc comment above the field and trailing comment on the same line
+		doc := commentText(field.Doc)
+		trailing := commentText(field.Comment)
+		fieldComment := doc
+		if trailing != "" {
+			if fieldComment != "" {
+				fieldComment += "\n"
+			}
+			fieldComment += trailing
+		}
 
-			// ✅ Step 1: Build a map of comments by line number
-			for _, commentGroup := range node.Comments {
-				for _, comment := range commentGroup.List {
-					pos := fset.Position(comment.Pos()).Line
-					commentMap[pos+1] = strings.TrimPrefix(comment.Text, "// ")
+		// ✅ Step 5: Store field information
+		var definition = serializer.FieldDefinition{
+			Name:        strings.Join(names, ", "),
+			Type:        typeStr,
+			Tag:         tag,
+			Description: fieldComment,
+		}
+		if len(names) == 0 || strings.Contains(reflect.StructTag(tag).Get("gorm"), "embedded") {
+			definition.Embedded = true
+			if len(names) == 0 {
+				definition.Name = strings.TrimPrefix(typeStr, "*")
+				if i := strings.LastIndex(definition.Name, "."); i > -1 {
+					definition.Name = definition.Name[i+1:]
 				}
 			}
+		}
+		structDef.Fields = append(structDef.Fields, definition)
 
-			// ✅ Step 2: Inspect AST to find the struct definition
-			ast.Inspect(node, func(n ast.Node) bool {
-				ts, ok := n.(*ast.TypeSpec)
-				if ok && ts.Name.Name == structName {
-					st, ok := ts.Type.(*ast.StructType)
-					if ok {
-						var sb strings.Builder
-						var description strings.Builder
-
-						// ✅ Step 3: Extract attached comments for the struct
-						if ts.Doc != nil {
-							for _, comment := range ts.Doc.List {
-								text := strings.TrimPrefix(comment.Text, "// ")
-								description.WriteString(text + "\n")
-								sb.WriteString(comment.Text + "\n")
-							}
+		// ✅ Step 5.1: Flatten embedded structs, resolving them across packages
+		if definition.Embedded {
+			if ep, es := resolveTypeExpr(p, spec.File, field.Type); es != nil {
+				if embedded, err := buildStructDefinition(ep, es, seen); err == nil {
+					for _, item := range embedded.Fields {
+						if declared[item.Name] {
+							continue
 						}
-
-						sb.WriteString(fmt.Sprintf("type %s struct {\n", structName))
-
-						for _, field := range st.Fields.List {
-							names := []string{}
-							for _, name := range field.Names {
-								names = append(names, name.Name)
-							}
-
-							// ✅ Step 4: Use getTypeString to resolve all types
-							typeStr := getTypeString(field.Type)
-
-							// ✅ Step 5: Extract struct tag
-							var tag string
-							if field.Tag != nil {
-								tag = strings.Trim(field.Tag.Value, "`")
-							}
-
-							// ✅ Step 6: Extract field comment from the map
-							pos := fset.Position(field.Pos()).Line
-							fieldComment := commentMap[pos]
-
-							// ✅ Step 7: Store field information
-							structDef.Fields = append(structDef.Fields, serializer.FieldDefinition{
-								Name:        strings.Join(names, ", "),
-								Type:        typeStr,
-								Tag:         tag,
-								Description: fieldComment,
-							})
-
-							// ✅ Step 8: Write fields to body
-							if fieldComment != "" {
-								sb.WriteString(fmt.Sprintf("    \n// %s\n", fieldComment))
-							}
-							sb.WriteString(fmt.Sprintf("    %s %s", strings.Join(names, ", "), typeStr))
-							if tag != "" {
-								sb.WriteString(fmt.Sprintf(" `%s`", tag))
-							}
-
-							sb.WriteString("\n")
-							/*if tag != "" {
-								if fieldComment != "" {
-									sb.WriteString(fmt.Sprintf("    %s %s `%s` // %s\n", strings.Join(names, ", "), typeStr, tag, fieldComment))
-								} else {
-									sb.WriteString(fmt.Sprintf("    %s %s `%s`\n", strings.Join(names, ", "), typeStr, tag))
-								}
-							} else {
-								if fieldComment != "" {
-									sb.WriteString(fmt.Sprintf("    %s %s // %s\n", strings.Join(names, ", "), typeStr, fieldComment))
-								} else {
-									sb.WriteString(fmt.Sprintf("    %s %s\n", strings.Join(names, ", "), typeStr))
-								}
-							}*/
+						if item.Origin == "" {
+							item.Origin = ep.Package.Name + "." + es.Spec.Name.Name
+						}
+						if ep != p {
+							item.Type = qualifyType(item.Type, ep.Package.Name)
 						}
+						structDef.Fields = append(structDef.Fields, item)
+					}
+				}
+			}
+		}
 
-						sb.WriteString("}\n")
+		// ✅ Step 6: Write fields to body, keeping blank lines between field groups
+		start := p.Fset.Position(field.Pos()).Line
+		if field.Doc != nil {
+			start = p.Fset.Position(field.Doc.Pos()).Line
+		}
+		if start-prevLine > 1 {
+			sb.WriteString("\n")
+		}
+		prevLine = p.Fset.Position(field.End()).Line
+		if doc != "" {
+			for _, line := range strings.Split(doc, "\n") {
+				sb.WriteString(strings.TrimRight("    // "+line, " ") + "\n")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s", strings.Join(names, ", "), typeStr))
+		if tag != "" {
+			sb.WriteString(fmt.Sprintf(" `%s`", tag))
+		}
+		if trailing != "" {
+			sb.WriteString(" // " + strings.ReplaceAll(trailing, "\n", " "))
+		}
 
-						// ✅ Step 9: Format the generated code using go/format
-						formattedCode, err := format.Source([]byte(sb.String()))
-						if err != nil {
-							return true // Ignore formatting errors and keep the original code
-						}
+		sb.WriteString("\n")
+	}
 
-						// ✅ Step 10: Store final values
-						structDef = serializer.StructDefinition{
-							Description: description.String(),
-							Body:        string(formattedCode),
-							Fields:      structDef.Fields,
-							File:        path,
-						}
+	sb.WriteString("}\n")
 
-						return false // Stop searching once the struct is found
-					}
+	// ✅ Step 7: Format the generated code using go/format, keeping the original code on errors
+	var body = sb.String()
+	if formattedCode, err := format.Source([]byte(body)); err == nil {
+		body = string(formattedCode)
+	}
+
+	// ✅ Step 8: Store final values
+	structDef.Description = commentText(spec.Doc)
+	structDef.Body = body
+	structDef.File = relativePath(spec.Path)
+	structDef.ExpandedBody = expandedBody(structDef.Description, structName, structDef.Fields)
+
+	return &structDef, nil
+}
+
+// expandedBody renders a struct definition with embedded structs replaced by their fields.
+func expandedBody(description, structName string, fields []serializer.FieldDefinition) string {
+	var sb strings.Builder
+	if description != "" {
+		for _, line := range strings.Split(description, "\n") {
+			sb.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+		}
+	}
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+	var origin = ""
+	var started = false
+	for _, field := range fields {
+		if field.Embedded {
+			continue
+		}
+		if field.Origin != origin || !started {
+			origin = field.Origin
+			if origin != "" {
+				if started {
+					sb.WriteString("\n")
 				}
-				return true
-			})
+				sb.WriteString("    // ↓ from " + origin + "\n")
+			} else if started {
+				sb.WriteString("\n")
+			}
+			started = true
 		}
-		return nil
-	})
+		if field.Description != "" {
+			for _, line := range strings.Split(field.Description, "\n") {
+				sb.WriteString(strings.TrimRight("    // "+line, " ") + "\n")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s", field.Name, field.Type))
+		if field.Tag != "" {
+			sb.WriteString(fmt.Sprintf(" `%s`", field.Tag))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
 
-	if err != nil {
-		return nil, err
+	if formattedCode, err := format.Source([]byte(sb.String())); err == nil {
+		return string(formattedCode)
+	}
+	return sb.String()
+}
+
+var localIdent = regexp.MustCompile(`(^|[^.\w])([A-Z]\w*)`)
+
+// qualifyType prefixes exported identifiers of a type string declared in another package.
+func qualifyType(typeStr, pkgName string) string {
+	return localIdent.ReplaceAllString(typeStr, "${1}"+pkgName+".${2}")
+}
+
+// resolveTypeExpr finds the declaration of a named type expression used in file,
+// following pointers, generic instantiations and package selectors.
+func resolveTypeExpr(p *parsedPackage, file *ast.File, expr ast.Expr) (*parsedPackage, *typeSpec) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return resolveTypeExpr(p, file, t.X)
+	case *ast.IndexExpr:
+		return resolveTypeExpr(p, file, t.X)
+	case *ast.IndexListExpr:
+		return resolveTypeExpr(p, file, t.X)
+	case *ast.Ident:
+		if spec, ok := p.Types[t.Name]; ok {
+			return p, spec
+		}
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		var path = importPath(file, x.Name, p.Package.Dir)
+		if path == "" {
+			return nil, nil
+		}
+		ep, err := loadParsedPackage(path, p.Package.Dir)
+		if err != nil {
+			return nil, nil
+		}
+		if spec, ok := ep.Types[t.Sel.Name]; ok {
+			return ep, spec
+		}
 	}
+	return nil, nil
+}
 
-	if structDef.Body == "" {
-		return nil, fmt.Errorf("struct definition not found")
+// importPath returns the import path bound to the given package name in file.
+func importPath(file *ast.File, name string, hint string) string {
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		if imp.Name != nil {
+			if imp.Name.Name == name {
+				return path
+			}
+			continue
+		}
+		if path == name || strings.HasSuffix(path, "/"+name) {
+			return path
+		}
+		if pkg, err := loadPackage(path, hint); err == nil && pkg.Name == name {
+			return path
+		}
 	}
+	return ""
+}
 
-	return &structDef, nil
+// commentText returns the text of a comment group without comment markers,
+// preserving line breaks of multi-line comments.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.TrimSpace(group.Text())
 }
 
 // ✅ Recursive function to resolve field types (including generics)
@@ -177,7 +324,41 @@ func getTypeString(expr ast.Expr) string {
 	case *ast.InterfaceType: // Interfaces
 		return "interface{}"
 	case *ast.StructType: // Inline structs
-		return "struct{...}"
+		var fields []string
+		for _, field := range t.Fields.List {
+			var names []string
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+			var item = getTypeString(field.Type)
+			if len(names) > 0 {
+				item = strings.Join(names, ", ") + " " + item
+			}
+			if field.Tag != nil {
+				item += " " + field.Tag.Value
+			}
+			fields = append(fields, item)
+		}
+		if len(fields) == 0 {
+			return "struct{}"
+		}
+		return "struct { " + strings.Join(fields, "; ") + " }"
+	case *ast.BasicLit: // Array lengths
+		return t.Value
+	case *ast.Ellipsis: // Variadic parameters
+		return "..." + getTypeString(t.Elt)
+	case *ast.ParenExpr:
+		return "(" + getTypeString(t.X) + ")"
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + getTypeString(t.Value)
+		case ast.RECV:
+			return "<-chan " + getTypeString(t.Value)
+		}
+		return "chan " + getTypeString(t.Value)
+	case *ast.FuncType:
+		return "func(...)"
 	case *ast.IndexExpr: // Generics like types.JSONType[map[string]interface{}]
 		base := getTypeString(t.X)
 		index := getTypeString(t.Index)
@@ -0,0 +1,91 @@
+package docify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/getevo/docify/serializer"
+	"github.com/getevo/evo/v2/lib/db"
+	"github.com/getevo/evo/v2/lib/gpath"
+	"github.com/getevo/restify"
+	"sort"
+)
+
+type Options = serializer.Options
+
+// Build documents the restify resources without touching global state, so it can run more than
+// once in the same process. Errors that leave the documentation usable, such as a missing
+// source file or a failing sample query, are joined into the returned error along with the Doc.
+func Build(ctx context.Context, opts Options) (*serializer.Doc, error) {
+	opts = opts.WithDefaults()
+	nextParseGeneration()
+	var doc = &serializer.Doc{}
+	var errs []error
+	if gpath.IsFileExist(opts.ProjectFile) {
+		if err := doc.ParseYaml(opts.ProjectFile); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", opts.ProjectFile, err)
+		}
+	}
+	if opts.Seed != nil {
+		doc.Settings.Seed = *opts.Seed
+	}
+
+	var resources = append([]*restify.Resource{}, opts.Resources...)
+	if len(resources) == 0 {
+		for idx := range restify.Resources {
+			resources = append(resources, restify.Resources[idx])
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Table < resources[j].Table
+	})
+	var sensitive = compileSensitive(doc.Settings)
+	for _, resource := range resources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entity, err := serializeEntity(resource, sensitive)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", resource.Name, err))
+		}
+		doc.Entities = append(doc.Entities, entity)
+	}
+
+	var m = map[string]*serializer.Entity{}
+	var tables = map[string]*serializer.Entity{}
+	for idx := range doc.Entities {
+		m[doc.Entities[idx].ID] = &doc.Entities[idx]
+		tables[doc.Entities[idx].Resource.Table] = &doc.Entities[idx]
+	}
+	for idx := range doc.Entities {
+		for i := range doc.Entities[idx].Association {
+			var association = &doc.Entities[idx].Association[i]
+			if v, ok := tables[association.Table]; ok && association.Table != "" {
+				association.Entity = v
+			} else if association.EntityName != "" {
+				association.Entity = m[association.EntityName]
+			}
+		}
+	}
+	linkForeignKeys(doc.Entities, tables)
+
+	var database = opts.DB
+	if database == nil && db.Enabled {
+		database = db.WithContext(ctx)
+	} else if database != nil {
+		database = database.WithContext(ctx)
+	}
+	// referenced entities are sampled first so foreign keys can reuse their primary keys
+	for _, entity := range sampleOrder(doc.Entities) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sample, err := ModelDataFaker(entity, doc.Settings, database)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		entity.DataSample = sample
+	}
+
+	return doc, errors.Join(errs...)
+}
@@ -0,0 +1,166 @@
+package docify
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// parsedPackage holds every type spec and typed constant of a package, parsed in a single pass.
+type parsedPackage struct {
+	Package *Package
+	Fset    *token.FileSet
+	Types   map[string]*typeSpec
+	Consts  map[string][]*constSpec
+	mtimes  map[string]time.Time
+
+	constBlocks int
+}
+
+// typeSpec is a type declaration along with the file it was found in.
+type typeSpec struct {
+	Spec *ast.TypeSpec
+	Doc  *ast.CommentGroup
+	File *ast.File
+	Path string
+}
+
+// parsedEntry guards the parse of one package, so unrelated packages are parsed concurrently.
+// checked is the generation in which the file mtimes were last compared.
+type parsedEntry struct {
+	mu      sync.Mutex
+	pkg     *parsedPackage
+	checked uint64
+}
+
+var parsed = map[string]*parsedEntry{}
+var parsedMu sync.Mutex
+
+// parseGeneration is advanced by every Build; within a generation the files of a parsed
+// package are not stat'ed again.
+var parseGeneration atomic.Uint64
+
+// nextParseGeneration makes the next lookups check whether source files changed.
+func nextParseGeneration() {
+	parseGeneration.Add(1)
+}
+
+// loadParsedPackage returns the parsed package for the given import path.
+// The package is parsed again only when one of its files is added, removed or modified;
+// files are checked once per generation.
+func loadParsedPackage(pkgPath string, hint string) (*parsedPackage, error) {
+	parsedMu.Lock()
+	entry, ok := parsed[pkgPath]
+	if !ok {
+		entry = &parsedEntry{}
+		parsed[pkgPath] = entry
+	}
+	parsedMu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	var generation = parseGeneration.Load()
+	if entry.pkg != nil && entry.checked == generation {
+		return entry.pkg, nil
+	}
+
+	pkg, err := loadPackage(pkgPath, hint)
+	if err != nil {
+		return nil, err
+	}
+	var files = pkg.Files()
+	var mtimes = make(map[string]time.Time, len(files))
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		mtimes[path] = info.ModTime()
+	}
+	if entry.pkg != nil && sameMtimes(entry.pkg.mtimes, mtimes) {
+		entry.checked = generation
+		return entry.pkg, nil
+	}
+
+	var p = parsedPackage{
+		Package: pkg,
+		Fset:    token.NewFileSet(),
+		Types:   map[string]*typeSpec{},
+		Consts:  map[string][]*constSpec{},
+		mtimes:  mtimes,
+	}
+	for _, path := range files {
+		node, err := parser.ParseFile(p.Fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		p.indexFile(node, path)
+	}
+	p.dropConversionCalls()
+	entry.pkg, entry.checked = &p, generation
+	return &p, nil
+}
+
+// indexFile records the type specs and typed constants of a parsed file.
+func (p *parsedPackage) indexFile(node *ast.File, path string) {
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if ok && gd.Tok == token.CONST {
+			p.indexConsts(gd)
+			continue
+		}
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			var doc = ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			p.Types[ts.Name.Name] = &typeSpec{
+				Spec: ts,
+				Doc:  doc,
+				File: node,
+				Path: path,
+			}
+		}
+	}
+}
+
+func sameMtimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, mtime := range a {
+		if v, ok := b[path]; !ok || !v.Equal(mtime) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupTypeSpec finds the declaration of a named type in the parse cache.
+func lookupTypeSpec(t reflect.Type) (*parsedPackage, *typeSpec, error) {
+	p, err := loadParsedPackage(t.PkgPath(), typeSourceDir(t))
+	if err != nil {
+		return nil, nil, err
+	}
+	// Generic instantiations are named like JSONType[...]
+	var name = t.Name()
+	if i := strings.IndexByte(name, '['); i > -1 {
+		name = name[:i]
+	}
+	spec, ok := p.Types[name]
+	if !ok {
+		return p, nil, fmt.Errorf("type %s not found in %s", name, t.PkgPath())
+	}
+	return p, spec, nil
+}
@@ -0,0 +1,181 @@
+package docify
+
+import (
+	"github.com/getevo/docify/serializer"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// constSpec is a typed constant declared in a const block.
+type constSpec struct {
+	Name        string
+	Value       constant.Value
+	Description string
+	Block       int
+}
+
+// indexConsts records the typed constants of a const block by type name.
+// Constants without an explicit value repeat the previous expression with the next iota,
+// following the Go spec.
+func (p *parsedPackage) indexConsts(gd *ast.GenDecl) {
+	p.constBlocks++
+	var typeName string
+	var values []ast.Expr
+	for iota, spec := range gd.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if vs.Type != nil || len(vs.Values) > 0 {
+			typeName = ""
+			if ident, ok := vs.Type.(*ast.Ident); ok {
+				typeName = ident.Name
+			}
+			values = vs.Values
+		}
+		for i, name := range vs.Names {
+			if i >= len(values) || name.Name == "_" {
+				continue
+			}
+			var expr = values[i]
+			var owner = typeName
+			// untyped constants converted explicitly, e.g. StatusActive = Status("active");
+			// owners that are not types of the package are dropped once it is parsed
+			if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+				if ident, ok := call.Fun.(*ast.Ident); ok {
+					if owner == "" {
+						owner = ident.Name
+					}
+					expr = call.Args[0]
+				}
+			}
+			if owner == "" {
+				continue
+			}
+			value := evalConst(expr, iota)
+			if value.Kind() == constant.Unknown {
+				continue
+			}
+			var description = commentText(vs.Doc)
+			if v := commentText(vs.Comment); v != "" {
+				description = strings.TrimSpace(description + "\n" + v)
+			}
+			p.Consts[owner] = append(p.Consts[owner], &constSpec{
+				Name:        name.Name,
+				Value:       value,
+				Description: description,
+				Block:       p.constBlocks,
+			})
+		}
+	}
+}
+
+// evalConst evaluates constant expressions made of literals, iota and arithmetic.
+func evalConst(expr ast.Expr, iota int) constant.Value {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true":
+			return constant.MakeBool(true)
+		case "false":
+			return constant.MakeBool(false)
+		}
+	case *ast.ParenExpr:
+		return evalConst(e.X, iota)
+	case *ast.UnaryExpr:
+		x := evalConst(e.X, iota)
+		if x.Kind() == constant.Unknown {
+			return x
+		}
+		return constant.UnaryOp(e.Op, x, 0)
+	case *ast.BinaryExpr:
+		x, y := evalConst(e.X, iota), evalConst(e.Y, iota)
+		if x.Kind() == constant.Unknown || y.Kind() == constant.Unknown {
+			return constant.MakeUnknown()
+		}
+		switch e.Op {
+		case token.SHL, token.SHR:
+			s, ok := constant.Uint64Val(y)
+			if !ok {
+				return constant.MakeUnknown()
+			}
+			return constant.Shift(x, e.Op, uint(s))
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, e.Op, y))
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				if constant.Sign(y) == 0 {
+					return constant.MakeUnknown()
+				}
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y)
+			}
+		}
+		return constant.BinaryOp(x, e.Op, y)
+	}
+	return constant.MakeUnknown()
+}
+
+// dropConversionCalls removes the constants of owners that are not types of the package,
+// recorded from calls such as format("x") that are not conversions.
+func (p *parsedPackage) dropConversionCalls() {
+	for name := range p.Consts {
+		if _, ok := p.Types[name]; !ok {
+			delete(p.Consts, name)
+		}
+	}
+}
+
+// lookupEnum returns the constants declared for a named string or numeric type.
+func lookupEnum(t reflect.Type) []serializer.EnumValue {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" || t.Name() == "" {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+	default:
+		return nil
+	}
+	p, err := loadParsedPackage(t.PkgPath(), typeSourceDir(t))
+	if err != nil {
+		return nil
+	}
+	return p.enum(t.Name())
+}
+
+// enum returns the values of a type when they are declared together: at least two constants
+// of the type in a single const block. Typed constants spread over the package, such as a
+// default size and a maximum size, do not make a closed set of values.
+func (p *parsedPackage) enum(name string) []serializer.EnumValue {
+	var items = p.Consts[name]
+	if len(items) < 2 {
+		return nil
+	}
+	for _, item := range items {
+		if item.Block != items[0].Block {
+			return nil
+		}
+	}
+	var values []serializer.EnumValue
+	for _, item := range items {
+		var value string
+		if item.Value.Kind() == constant.String {
+			value = constant.StringVal(item.Value)
+		} else {
+			value = item.Value.ExactString()
+		}
+		values = append(values, serializer.EnumValue{
+			Name:        item.Name,
+			Value:       value,
+			Description: item.Description,
+		})
+	}
+	return values
+}
@@ -0,0 +1,85 @@
+package docify
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const enumSource = `package app
+
+type Status string
+
+const (
+	StatusActive Status = "active" // can log in
+	StatusBlocked       = Status("blocked")
+)
+
+type Priority int
+
+const (
+	Low Priority = iota
+	Medium
+	High
+)
+
+type Size int
+
+const DefaultSize Size = 10
+
+const MaxSize Size = 100
+
+type Timeout int
+
+const DefaultTimeout Timeout = 30
+
+type Format string
+
+func format(s string) string { return s }
+
+const (
+	Plain = format("plain")
+	Rich  = format("rich")
+)
+`
+
+func TestParsedPackageEnum(t *testing.T) {
+	var fset = token.NewFileSet()
+	node, err := parser.ParseFile(fset, "app.go", enumSource, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p = parsedPackage{Fset: fset, Types: map[string]*typeSpec{}, Consts: map[string][]*constSpec{}}
+	p.indexFile(node, "app.go")
+	p.dropConversionCalls()
+
+	var tests = []struct {
+		name   string
+		values []string
+	}{
+		{"Status", []string{"active", "blocked"}},
+		{"Priority", []string{"0", "1", "2"}},
+		{"Size", nil},
+		{"Timeout", nil},
+		{"format", nil},
+		{"Format", nil},
+	}
+	for _, test := range tests {
+		var values = p.enum(test.name)
+		if len(values) != len(test.values) {
+			t.Errorf("%s: got %+v, want %v", test.name, values, test.values)
+			continue
+		}
+		for i, value := range values {
+			if value.Value != test.values[i] {
+				t.Errorf("%s: value %d is %s, want %s", test.name, i, value.Value, test.values[i])
+			}
+		}
+	}
+	if values := p.enum("Status"); values[0].Description != "can log in" {
+		t.Errorf("comments are not kept: %+v", values[0])
+	}
+	if _, ok := p.Consts["format"]; ok {
+		t.Error("function calls are not conversions")
+	}
+}
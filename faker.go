@@ -0,0 +1,536 @@
+package docify
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/getevo/docify/serializer"
+	"github.com/shopspring/decimal"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FakerFunc returns a sample value for a field, or nil to fall back to the next generator.
+type FakerFunc func(f *gofakeit.Faker, field serializer.Field) interface{}
+
+type nameFaker struct {
+	pattern *regexp.Regexp
+	fn      FakerFunc
+}
+
+var typeFakers = map[reflect.Type]FakerFunc{}
+var nameFakers []nameFaker
+var fakersMu sync.RWMutex
+
+// RegisterTypeFaker registers a sample generator for fields of type t, e.g. an application's own
+// money or ID type. It takes precedence over every built-in generator.
+func RegisterTypeFaker(t reflect.Type, fn FakerFunc) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fakersMu.Lock()
+	defer fakersMu.Unlock()
+	typeFakers[t] = fn
+}
+
+// RegisterNameFaker registers a sample generator for fields whose json or column name matches
+// the given regular expression. Generators registered later take precedence.
+func RegisterNameFaker(pattern string, fn FakerFunc) {
+	var compiled = regexp.MustCompile(pattern)
+	fakersMu.Lock()
+	defer fakersMu.Unlock()
+	nameFakers = append([]nameFaker{{pattern: compiled, fn: fn}}, nameFakers...)
+}
+
+// formatFakers generate values for string formats derived from validation rules.
+var formatFakers = map[string]FakerFunc{
+	"email":          func(f *gofakeit.Faker, _ serializer.Field) interface{} { return strings.ToLower(f.Email()) },
+	"uri":            func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.URL() },
+	"hostname":       func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.DomainName() },
+	"uuid":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.UUID() },
+	"ipv4":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IPv4Address() },
+	"ipv6":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IPv6Address() },
+	"cidr":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IPv4Address() + "/24" },
+	"mac":            func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.MacAddress() },
+	"date":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return fakeTime(f).Format("2006-01-02") },
+	"date-time":      func(f *gofakeit.Faker, _ serializer.Field) interface{} { return fakeTime(f).Format(time.RFC3339) },
+	"unix-timestamp": func(f *gofakeit.Faker, _ serializer.Field) interface{} { return fakeTime(f).Unix() },
+	"duration":       func(f *gofakeit.Faker, _ serializer.Field) interface{} { return fmt.Sprintf("%dm", f.IntRange(1, 120)) },
+	"cron": func(f *gofakeit.Faker, _ serializer.Field) interface{} {
+		return fmt.Sprintf("0 %d * * *", f.IntRange(0, 23))
+	},
+	"timezone":    func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.TimeZoneRegion() },
+	"phone":       fakePhone,
+	"e164":        fakePhone,
+	"json":        func(f *gofakeit.Faker, _ serializer.Field) interface{} { return "{}" },
+	"credit-card": func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.CreditCardNumber(nil) },
+	"hex-color":   func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.HexColor() },
+	"rgb-color": func(f *gofakeit.Faker, _ serializer.Field) interface{} {
+		var c = f.RGBColor()
+		return fmt.Sprintf("rgb(%d,%d,%d)", c[0], c[1], c[2])
+	},
+	"rgba-color": func(f *gofakeit.Faker, _ serializer.Field) interface{} {
+		var c = f.RGBColor()
+		return fmt.Sprintf("rgba(%d,%d,%d,1)", c[0], c[1], c[2])
+	},
+	"country-alpha2": func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.CountryAbr() },
+	"latitude":       func(f *gofakeit.Faker, _ serializer.Field) interface{} { return round(f.Latitude(), 6) },
+	"longitude":      func(f *gofakeit.Faker, _ serializer.Field) interface{} { return round(f.Longitude(), 6) },
+	"port":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IntRange(1024, 65535) },
+	"btc-address":    func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.BitcoinAddress() },
+	"name":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Name() },
+	"alpha":          func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.LetterN(8) },
+	"latin":          func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.LetterN(8) },
+	"alphanumeric":   func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Lexify("???") + f.DigitN(3) },
+	"digit":          func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.DigitN(6) },
+	"hex":            func(f *gofakeit.Faker, _ serializer.Field) interface{} { return fmt.Sprintf("%x", f.Uint32()) },
+	"lowercase":      func(f *gofakeit.Faker, _ serializer.Field) interface{} { return strings.ToLower(f.Word()) },
+	"uppercase":      func(f *gofakeit.Faker, _ serializer.Field) interface{} { return strings.ToUpper(f.Word()) },
+	"slug":           fakeSlug,
+	"text":           func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Sentence(6) },
+	"no-html":        func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Sentence(6) },
+	"safe-html":      func(f *gofakeit.Faker, _ serializer.Field) interface{} { return "<p>" + f.Sentence(6) + "</p>" },
+}
+
+func init() {
+	RegisterTypeFaker(reflect.TypeOf(time.Time{}), func(f *gofakeit.Faker, _ serializer.Field) interface{} {
+		return fakeTime(f)
+	})
+	RegisterTypeFaker(reflect.TypeOf(decimal.Decimal{}), fakeDecimal)
+
+	// most specific patterns last, as later registrations take precedence
+	for _, item := range []struct {
+		pattern string
+		fn      FakerFunc
+	}{
+		{`(^|_)(description|summary|bio|about|note|notes|comment|content|body|message)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Sentence(8) }},
+		{`(^|_)(title|subject|headline)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return strings.TrimSuffix(f.Sentence(4), ".") }},
+		{`(^|_)name$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Name() }},
+		{`(^|_)(product|item)_?name$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.ProductName() }},
+		{`(^|_)(company|organization|organisation)(_name)?$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Company() }},
+		{`(^|_)first_?name$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.FirstName() }},
+		{`(^|_)(last_?name|surname|family_?name)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.LastName() }},
+		{`(^|_)(user_?name|login|nickname)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Username() }},
+		{`(^|_)(password|passwd|secret)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} {
+			return f.Password(true, true, true, true, false, 12)
+		}},
+		{`(^|_)job_?title$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.JobTitle() }},
+		{`(^|_)(phone|mobile|tel|telephone|fax|cell)(_number|_no)?$`, fakePhone},
+		{`(^|_)(street|address|address_?line_?\d?)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Street() }},
+		{`(^|_)city$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.City() }},
+		{`(^|_)(state|province|region)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.State() }},
+		{`(^|_)country$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Country() }},
+		{`(^|_)country_?(code|iso)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.CountryAbr() }},
+		{`(^|_)(zip|zip_?code|postal_?code|post_?code)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Zip() }},
+		{`(^|_)(lat|latitude)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return round(f.Latitude(), 6) }},
+		{`(^|_)(lng|lon|long|longitude)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return round(f.Longitude(), 6) }},
+		{`(^|_)(url|uri|link|website|homepage|avatar|image|photo|picture|thumbnail)(_url)?$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.URL() }},
+		{`(^|_)(domain|host|hostname)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.DomainName() }},
+		{`(^|_)(ip|ip_?address)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IPv4Address() }},
+		{`(^|_)(uuid|guid)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.UUID() }},
+		{`(^|_)slug$`, fakeSlug},
+		{`(^|_)(color|colour)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.HexColor() }},
+		{`(^|_)currency(_code)?$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.CurrencyShort() }},
+		{`(^|_)(language|lang|locale)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.LanguageAbbreviation() }},
+		{`(^|_)(timezone|time_zone|tz)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.TimeZoneRegion() }},
+		{`(^|_)gender$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Gender() }},
+		{`(^|_)(price|amount|cost|total|subtotal|balance|fee|salary)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Price(1, 1000) }},
+		{`(^|_)(quantity|qty|count)$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IntRange(1, 20) }},
+		{`(^|_)age$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IntRange(18, 80) }},
+		{`(^|_)year$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.IntRange(2000, 2025) }},
+		{`(^|_)e_?mail(_address)?$`, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return strings.ToLower(f.Email()) }},
+	} {
+		RegisterNameFaker(item.pattern, item.fn)
+	}
+}
+
+// newFaker returns a faker seeded from the project seed and the given names, so that every
+// entity and field gets its own stable sequence of values, unaffected by the others.
+func newFaker(seed int64, names ...string) *gofakeit.Faker {
+	var h = fnv.New64a()
+	_, _ = fmt.Fprint(h, seed)
+	for _, name := range names {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(name))
+	}
+	return gofakeit.New(h.Sum64())
+}
+
+// fakeField fills a column with a sample value chosen by Go type, enum, validation rules and
+// field name, falling back to a random value of the field kind. The result respects size limits.
+func fakeField(f *gofakeit.Faker, field reflect.Value, item serializer.Field) {
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+	var t = field.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// the registries are read under the lock, the generators run without it
+	fakersMu.RLock()
+	var fakers = semanticFakers(t, item)
+	fakersMu.RUnlock()
+	for _, fn := range fakers {
+		// formatted values can not be cut to size, so they are regenerated until one fits
+		for attempt := 0; attempt < 5; attempt++ {
+			if value := fn(f, item); value != nil && fitsLength(value, item) && assignValue(field, value) {
+				conformToRules(f, field, item)
+				return
+			}
+		}
+	}
+
+	fakeValue(f, field, 0)
+	conformToRules(f, field, item)
+}
+
+// semanticFakers lists the generators applicable to a field, most specific first.
+// The caller holds fakersMu.
+func semanticFakers(t reflect.Type, item serializer.Field) []FakerFunc {
+	var list []FakerFunc
+	if fn, ok := typeFakers[t]; ok {
+		list = append(list, fn)
+	}
+	if len(item.Enum) > 0 {
+		list = append(list, func(f *gofakeit.Faker, field serializer.Field) interface{} {
+			return field.Enum[f.IntN(len(field.Enum))]
+		})
+	}
+	if pattern := item.Rules.Pattern(); pattern != "" {
+		list = append(list, func(f *gofakeit.Faker, _ serializer.Field) interface{} { return f.Regex(pattern) })
+	}
+	var format = item.Rules.Format()
+	if format == "" {
+		format = item.Format
+	}
+	if fn, ok := formatFakers[format]; ok {
+		list = append(list, fn)
+	}
+	for _, name := range []string{strings.ToLower(item.JsonTag), strings.ToLower(item.DBName)} {
+		for _, nf := range nameFakers {
+			if name != "" && nf.pattern.MatchString(name) {
+				list = append(list, nf.fn)
+			}
+		}
+	}
+	if item.Example != nil {
+		list = append(list, func(_ *gofakeit.Faker, field serializer.Field) interface{} { return field.Example })
+	}
+	return list
+}
+
+// assignValue sets a generated value if it can be converted to the field type.
+// Strings are parsed into numeric and boolean fields, and enum values are converted as needed.
+func assignValue(field reflect.Value, value interface{}) bool {
+	var target = field
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	var v = reflect.ValueOf(value)
+	if v.Type().AssignableTo(target.Type()) {
+		target.Set(v)
+		return true
+	}
+	if v.Kind() == reflect.String && target.CanAddr() {
+		if u, ok := target.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(v.String())) == nil
+		}
+	}
+	if v.Kind() == reflect.String && target.Kind() != reflect.String {
+		return setEnumValue(target, v.String())
+	}
+	if v.Type().ConvertibleTo(target.Type()) && (v.Kind() == reflect.String) == (target.Kind() == reflect.String) {
+		target.Set(v.Convert(target.Type()))
+		return true
+	}
+	return false
+}
+
+func fakePhone(f *gofakeit.Faker, _ serializer.Field) interface{} {
+	return "+1" + f.Numerify("##########")
+}
+
+func fakeSlug(f *gofakeit.Faker, _ serializer.Field) interface{} {
+	return strings.ToLower(f.Word() + "-" + f.Word())
+}
+
+func fakeTime(f *gofakeit.Faker) time.Time {
+	var start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return f.DateRange(start, start.AddDate(5, 0, 0)).Truncate(time.Second).UTC()
+}
+
+// fakeDecimal returns a decimal that fits the column precision and scale, two decimals when the
+// column does not declare them.
+func fakeDecimal(f *gofakeit.Faker, field serializer.Field) interface{} {
+	var scale, max = 2, 1000.0
+	if field.Precision > 0 {
+		scale = field.Scale
+		max = math.Min(max, math.Pow10(field.Precision-scale)-math.Pow10(-scale))
+	}
+	// columns without integer digits, such as decimal(2,2), only hold values below 1,
+	// so the range starts at the smallest step of the scale
+	var min = math.Pow10(-scale)
+	return decimal.NewFromFloat(f.Float64Range(min, max)).Round(int32(scale))
+}
+
+func round(v float64, digits int) float64 {
+	var p = math.Pow10(digits)
+	return math.Round(v*p) / p
+}
+
+// fakeValue fills v with random data, expanding structs, slices, maps and JSON column wrappers
+func fakeValue(f *gofakeit.Faker, v reflect.Value, depth int) {
+	if depth > 3 || !v.CanSet() {
+		return
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		fakeValue(f, v.Elem(), depth)
+		return
+	}
+	fakersMu.RLock()
+	fn, ok := typeFakers[v.Type()]
+	fakersMu.RUnlock()
+	if ok {
+		if value := fn(f, serializer.Field{}); value != nil && assignValue(v, value) {
+			return
+		}
+	}
+	if inner := serializer.ValueType(v.Type()); inner != v.Type() {
+		var value = reflect.New(inner)
+		fakeValue(f, value.Elem(), depth+1)
+		if b, err := json.Marshal(value.Interface()); err == nil && v.CanAddr() {
+			if u, ok := v.Addr().Interface().(json.Unmarshaler); ok {
+				_ = u.UnmarshalJSON(b)
+			}
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		setFieldValue(v, f.IntRange(1, 100))
+	case reflect.Float64, reflect.Float32:
+		setFieldValue(v, round(f.Float64Range(1, 100), 2))
+	case reflect.String:
+		setFieldValue(v, f.Word())
+	case reflect.Bool:
+		setFieldValue(v, f.Bool())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fakeValue(f, v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		var slice = reflect.MakeSlice(v.Type(), 1, 1)
+		fakeValue(f, slice.Index(0), depth+1)
+		v.Set(slice)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		var m = reflect.MakeMap(v.Type())
+		var value = reflect.New(v.Type().Elem()).Elem()
+		fakeValue(f, value, depth+1)
+		m.SetMapIndex(reflect.ValueOf(f.Word()).Convert(v.Type().Key()), value)
+		v.Set(m)
+	default:
+
+	}
+}
+
+// conformToRules adjusts a faked value so that it passes length, range and one-of validation
+// rules and fits the column size
+func conformToRules(f *gofakeit.Faker, field reflect.Value, item serializer.Field) {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return
+		}
+		field = field.Elem()
+	}
+	if !field.CanSet() {
+		return
+	}
+	var rules = item.Rules
+	if rule, ok := rules.Find(serializer.RuleOneOf); ok && len(rule.Values) > 0 {
+		setEnumValue(field, f.RandomString(rule.Values))
+		return
+	}
+	if field.Type() == reflect.TypeOf(decimal.Decimal{}) {
+		var value = field.Interface().(decimal.Decimal)
+		var scale = int32(2)
+		if item.Precision > 0 {
+			scale = int32(item.Scale)
+		}
+		var n, _ = value.Float64()
+		n = conformNumber(n, math.Pow10(-int(scale)), rules)
+		field.Set(reflect.ValueOf(decimal.NewFromFloat(n).Round(scale)))
+		return
+	}
+	switch field.Kind() {
+	case reflect.String:
+		var value = field.String()
+		if !fitsLength(value, item) {
+			value = fakeText(f, item)
+		}
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		var value = field.Convert(reflect.TypeOf(float64(0))).Float()
+		var integer = field.Kind() != reflect.Float32 && field.Kind() != reflect.Float64
+		var step = 0.01
+		if integer {
+			step = 1
+		}
+		value = conformNumber(value, step, rules)
+		if integer {
+			value = math.Round(value)
+		}
+		setFieldValue(field, value)
+	}
+}
+
+// conformNumber moves a number into the range of the rules, step being the smallest increment
+// of the field, and away from excluded values.
+func conformNumber(value, step float64, rules serializer.ValidationRules) float64 {
+	for _, rule := range rules {
+		switch rule.Kind {
+		case serializer.RuleMinimum:
+			if value < rule.Number || (rule.Exclusive && value == rule.Number) {
+				value = rule.Number
+				if rule.Exclusive {
+					value += step
+				}
+			}
+		case serializer.RuleMaximum:
+			if value > rule.Number || (rule.Exclusive && value == rule.Number) {
+				value = rule.Number
+				if rule.Exclusive {
+					value -= step
+				}
+			}
+		case serializer.RuleEqual:
+			return rule.Number
+		case serializer.RuleInteger, serializer.RuleNumber:
+			if rule.Value == "-" && value > 0 {
+				value = -value
+			}
+		}
+	}
+	for _, rule := range rules {
+		if rule.Kind != serializer.RuleNotEqual || value != rule.Number {
+			continue
+		}
+		if max, ok := rules.Find(serializer.RuleMaximum); ok && value+step > max.Number {
+			value -= step
+		} else {
+			value += step
+		}
+	}
+	return value
+}
+
+// lengthRange returns the length bounds of a string field from its rules and column size.
+func lengthRange(item serializer.Field) (int, int) {
+	min, max := item.Rules.LengthRange()
+	if item.Size > 0 && (max == -1 || item.Size < max) {
+		max = item.Size
+	}
+	return min, max
+}
+
+// fitsLength reports whether a generated value passes the length rules of a string field;
+// values of other types always fit.
+func fitsLength(value interface{}, item serializer.Field) bool {
+	s, ok := value.(string)
+	if !ok {
+		return true
+	}
+	var n = utf8.RuneCountInString(s)
+	min, max := lengthRange(item)
+	if (min > -1 && n < min) || (max > -1 && n > max) {
+		return false
+	}
+	for _, rule := range item.Rules {
+		if rule.Kind == serializer.RuleNotLength && n == int(rule.Number) {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeText returns letters of a length allowed by the rules of a string field.
+func fakeText(f *gofakeit.Faker, item serializer.Field) string {
+	min, max := lengthRange(item)
+	if min < 1 {
+		min = 1
+	}
+	if max == -1 {
+		max = min + 10
+	}
+	if max < min {
+		return ""
+	}
+	var value = f.LetterN(uint(f.IntRange(min, max)))
+	for attempt := 0; attempt < 5 && !fitsLength(value, item); attempt++ {
+		value = f.LetterN(uint(f.IntRange(min, max)))
+	}
+	return value
+}
+
+// setEnumValue assigns a value given as string to a string, numeric or boolean field
+func setEnumValue(field reflect.Value, value string) bool {
+	var kind = field.Type()
+	for kind.Kind() == reflect.Ptr {
+		kind = kind.Elem()
+	}
+	switch kind.Kind() {
+	case reflect.String:
+		setFieldValue(field, value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		setFieldValue(field, v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		setFieldValue(field, v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		setFieldValue(field, v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return false
+		}
+		setFieldValue(field, v)
+	default:
+		return false
+	}
+	return true
+}
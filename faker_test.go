@@ -0,0 +1,101 @@
+package docify
+
+import (
+	"net/mail"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/getevo/docify/serializer"
+	"github.com/shopspring/decimal"
+)
+
+func TestFakeFieldFitsLength(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		var email string
+		var item = serializer.Field{JsonTag: "email", Size: 20, Rules: serializer.ParseValidation("email")}
+		fakeField(newFaker(seed, "email"), reflect.ValueOf(&email).Elem(), item)
+		if utf8.RuneCountInString(email) > 20 {
+			t.Fatalf("seed %d: %q exceeds the column size", seed, email)
+		}
+		if strings.Contains(email, "@") {
+			if _, err := mail.ParseAddress(email); err != nil {
+				t.Fatalf("seed %d: formatted value %q was cut: %v", seed, email, err)
+			}
+		}
+
+		var code string
+		item = serializer.Field{JsonTag: "code", Rules: serializer.ParseValidation("len>=3,len<=5,len!=4")}
+		fakeField(newFaker(seed, "code"), reflect.ValueOf(&code).Elem(), item)
+		if n := utf8.RuneCountInString(code); n < 3 || n > 5 || n == 4 {
+			t.Fatalf("seed %d: %q breaks the length rules", seed, code)
+		}
+	}
+}
+
+func TestFakeDecimalPrecision(t *testing.T) {
+	var tests = []struct {
+		precision, scale int
+		rules            string
+		min, max         float64
+	}{
+		{2, 2, "", 0.01, 0.99},
+		{5, 2, "", 0.01, 999.99},
+		{4, 0, "", 1, 999},
+		{10, 2, ">=500,<=600", 500, 600},
+		{0, 0, "", 0.01, 1000},
+	}
+	for _, test := range tests {
+		var item = serializer.Field{JsonTag: "ratio", Precision: test.precision, Scale: test.scale, Rules: serializer.ParseValidation(test.rules)}
+		for seed := int64(0); seed < 50; seed++ {
+			var value decimal.Decimal
+			fakeField(newFaker(seed, "ratio"), reflect.ValueOf(&value).Elem(), item)
+			var n, _ = value.Float64()
+			if n < test.min || n > test.max {
+				t.Fatalf("decimal(%d,%d) %q: %v outside [%v, %v]", test.precision, test.scale, test.rules, n, test.min, test.max)
+			}
+		}
+	}
+}
+
+func TestConformToRules(t *testing.T) {
+	var item = serializer.Field{Rules: serializer.ParseValidation("enum")}
+	item.Rules[0].Values = []string{"draft", "published", "archived"}
+	var seen = map[string]bool{}
+	for seed := int64(0); seed < 50; seed++ {
+		var status string
+		conformToRules(newFaker(seed, "status"), reflect.ValueOf(&status).Elem(), item)
+		seen[status] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("one-of values are not varied: %v", seen)
+	}
+
+	var tests = []struct {
+		rules string
+		value int
+		want  int
+	}{
+		{">=10", 3, 10},
+		{">10", 3, 11},
+		{"<5", 9, 4},
+		{"!=7", 7, 8},
+		{"<=7,!=7", 7, 6},
+		{"==3", 9, 3},
+		{"-int", 4, -4},
+	}
+	for _, test := range tests {
+		var value = test.value
+		conformToRules(newFaker(1), reflect.ValueOf(&value).Elem(), serializer.Field{Rules: serializer.ParseValidation(test.rules)})
+		if value != test.want {
+			t.Errorf("conformToRules(%d, %q) = %d, want %d", test.value, test.rules, value, test.want)
+		}
+	}
+}
+
+func TestNewFakerIsSeeded(t *testing.T) {
+	if newFaker(7, "a", "b").Word() != newFaker(7, "a", "b").Word() {
+		t.Error("the same seed and names must produce the same values")
+	}
+}
@@ -4,162 +4,255 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/getevo/docify/serializer"
-	"github.com/getevo/evo/v2/lib/db"
 	scm "github.com/getevo/evo/v2/lib/db/schema"
-	"github.com/getevo/evo/v2/lib/gpath"
 	"github.com/getevo/evo/v2/lib/log"
-	"github.com/getevo/evo/v2/lib/text"
 	"github.com/getevo/restify"
-	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
-	"math/rand"
 	"reflect"
 	"regexp"
 	"sort"
 	"strings"
 )
 
-var doc = serializer.Doc{}
-
-func SerializeEntities() {
-
-	if gpath.IsFileExist("project.yml") {
-		doc.ParseYaml("project.yml")
+// serializeEntity documents a restify resource: its source definition, fields, keys,
+// indexes, associations and endpoints. A missing source definition is reported but not fatal.
+func serializeEntity(resource *restify.Resource, sensitive []sensitiveRule) (serializer.Entity, error) {
+	def, defErr := GetStructDefinition(resource.Type)
+	if defErr != nil {
+		def = &serializer.StructDefinition{}
 	}
-	var resources []*restify.Resource
-	for idx, _ := range restify.Resources {
-		resources = append(resources, restify.Resources[idx])
+	var chunks = strings.Split(resource.Name, ".")
+	var entity = serializer.Entity{
+		ID:          resource.Name,
+		Name:        chunks[1],
+		Description: def.Description,
+		Pkg:         chunks[0],
+		Path:        resource.Type.PkgPath(),
+		Resource:    resource,
+		Endpoints:   resource.Actions,
 	}
-	sort.Slice(resources, func(i, j int) bool {
-		return resources[i].Table < resources[j].Table
-	})
-	var m = map[string]*serializer.Entity{}
-	for i, resource := range resources {
-		def, err := GetStructDefinition(resource.Type)
-		if err != nil{
-			log.Error(err)
-			def = &serializer.StructDefinition{}
-		}
-		var chunks = strings.Split(resource.Name, ".")
-		var entity = serializer.Entity{
-			ID:          resource.Name,
-			Name:        chunks[1],
-			Description: def.Description,
-			Pkg:         chunks[0],
-			Path:        resource.Type.PkgPath(),
-			Resource:    resources[i],
-		}
-		var fields []serializer.Field
-		log.Info("Parsing fields for entity:", entity.Name)
-		entity.Definition = def
-		if err != nil {
-			log.Error(err)
+	var fields []serializer.Field
+	var indexes = parseIndexes(resource.Schema)
+	var comments = map[string]string{}
+	for _, item := range def.Fields {
+		// a definition such as `A, B string` documents every name it declares
+		for _, name := range strings.Split(item.Name, ", ") {
+			// fields declared on the struct itself shadow the promoted ones
+			if _, ok := comments[name]; !ok || item.Origin == "" {
+				comments[name] = item.Description
+			}
 		}
-		for _, field := range resource.Schema.Fields {
-			if field.DBName == "" {
-				var f = field.FieldType
-				for f.Kind() == reflect.Ptr {
-					f = f.Elem()
+	}
+	log.Info("Parsing fields for entity:", entity.Name)
+	entity.Definition = def
+	for _, field := range resource.Schema.Fields {
+		if field.DBName == "" {
+			if association, ok := serializer.NewAssociation(field, resource.Schema); ok {
+				// associations hidden from json can not be loaded through the API
+				var visibility = serializer.NewVisibility(field)
+				if visibility.Hidden {
+					continue
 				}
-				switch f.Kind() {
-				case reflect.Struct:
+				association.JsonTag = visibility.JsonName
+				entity.Association = append(entity.Association, association)
+			}
+			continue
+		}
 
-					entity.Association = append(entity.Association, serializer.Association{
-						Name:       field.Name,
-						EntityName: f.String(),
-						Array:      false,
-					})
-				case reflect.Slice:
-					entity.Association = append(entity.Association, serializer.Association{
-						Name:  field.Name,
-						Array: true,
-					})
-				default:
+		var fieldDoc = serializer.Field{
+			Name:          field.Name,
+			GoType:        field.FieldType.String(),
+			DBName:        field.DBName,
+			AutoIncrement: field.AutoIncrement,
+			PrimaryKey:    field.PrimaryKey,
+			Unique:        field.Unique,
+			Nullable:      field.NotNull || field.FieldType.Kind() == reflect.Ptr,
+			DBType:        getDBType(field),
+			Size:          field.Size,
+			Precision:     field.Precision,
+			Scale:         field.Scale,
+		}
 
+		for _, index := range indexes {
+			for _, name := range index.Fields {
+				if name != field.DBName {
+					continue
+				}
+				fieldDoc.Indexed = true
+				if index.Unique {
+					fieldDoc.UniqueIndex = joinName(fieldDoc.UniqueIndex, index.Name)
+				} else {
+					fieldDoc.Index = joinName(fieldDoc.Index, index.Name)
 				}
-				continue
-			}
-
-			var fieldDoc = serializer.Field{
-				Name:          field.Name,
-				GoType:        field.FieldType.String(),
-				DBName:        field.DBName,
-				AutoIncrement: field.AutoIncrement,
-				PrimaryKey:    field.PrimaryKey,
-				Unique:        field.Unique,
-				Nullable:      field.NotNull || field.FieldType.Kind() == reflect.Ptr,
 			}
+		}
 
-			fieldDoc.JsonTag = strings.Split(field.Tag.Get("json"), ",")[0]
-			if fieldDoc.JsonTag == "" {
-				fieldDoc.JsonTag = field.Name
-			}
-			fieldDoc.JsonType = getJsonType(field)
-			if v, ok := field.TagSettings["TYPE"]; ok && strings.HasPrefix(v, "enum") {
-				fieldDoc.Enum = ExtractEnumValues(v)
-				fieldDoc.JsonType = "string" // Enum fields are treated as strings for now.
+		fieldDoc.Visibility = serializer.NewVisibility(field)
+		fieldDoc.JsonTag = fieldDoc.Visibility.JsonName
+		var mapping = serializer.MapType(field.FieldType)
+		fieldDoc.JsonType = mapping.JsonType
+		fieldDoc.Format = mapping.Format
+		fieldDoc.Example = mapping.Example
+		if nested := serializer.DescribeType(field.FieldType); nested.IsComplex() {
+			fieldDoc.JsonType = nested.JsonType
+			fieldDoc.Properties = nested.Properties
+			fieldDoc.Items = nested.Items
+			fieldDoc.Values = nested.Values
+			describeNested(&fieldDoc, field.FieldType)
+		}
+		if v, ok := field.TagSettings["TYPE"]; ok && strings.HasPrefix(v, "enum") {
+			fieldDoc.Enum = ExtractEnumValues(v)
+			fieldDoc.JsonType = "string" // Enum fields are treated as strings for now.
+		} else if values := lookupEnum(field.FieldType); len(values) > 0 {
+			fieldDoc.EnumValues = values
+			for _, item := range values {
+				fieldDoc.Enum = append(fieldDoc.Enum, item.Value)
 			}
-			fieldDoc.Default = field.DefaultValue
+		}
+		fieldDoc.Sensitive = getSensitivity(field.Tag, fieldDoc, sensitive)
+		fieldDoc.Default = field.DefaultValue
+		fieldDoc.Description = comments[field.Name]
+		if fieldDoc.Description == "" {
 			fieldDoc.Description = field.Comment
-			fieldDoc.Validation = field.Tag.Get("validation")
-
-			entity.Endpoints = restify.Resources[resource.Table].Actions
+		}
+		if fieldDoc.Description == "" {
+			fieldDoc.Description = mapping.Description
+		}
+		fieldDoc.Validation = field.Tag.Get("validation")
+		fieldDoc.Rules = serializer.ParseValidation(fieldDoc.Validation)
+		for i := range fieldDoc.Rules {
+			if fieldDoc.Rules[i].Kind == serializer.RuleOneOf {
+				fieldDoc.Rules[i].Values = fieldDoc.Enum
+			}
+		}
 
-			if v, ok := field.TagSettings["FK"]; ok {
-				chunks = strings.Split(v, ".")
-				var s = scm.Find(chunks[0])
-				if s != nil {
-					var fk = &serializer.ForeignKey{
-						Table: chunks[0],
-					}
-					if len(chunks) > 1 {
-						fk.Field = chunks[1]
-					} else {
-						if len(s.PrimaryKey) > 0 {
-							fk.Field = s.PrimaryKey[0]
-						}
+		if v, ok := field.TagSettings["FK"]; ok {
+			chunks = strings.Split(v, ".")
+			var s = scm.Find(chunks[0])
+			if s != nil {
+				var fk = &serializer.ForeignKey{
+					Table: chunks[0],
+				}
+				if len(chunks) > 1 {
+					fk.Field = chunks[1]
+				} else {
+					if len(s.PrimaryKey) > 0 {
+						fk.Field = s.PrimaryKey[0]
 					}
-					fieldDoc.ForeignKey = fk
 				}
+				fieldDoc.ForeignKey = fk
+			}
+		}
+		fields = append(fields, fieldDoc)
+	}
+	entity.Fields = fields
+	entity.Indexes = indexes
+	for _, primary := range resource.Schema.PrimaryFields {
+		for _, item := range fields {
+			if item.Name == primary.Name {
+				entity.PrimaryKey = append(entity.PrimaryKey, item)
 			}
-			fields = append(fields, fieldDoc)
 		}
-		entity.Fields = fields
-		entity.DataSample = ModelDataFaker(&entity)
-		doc.Entities = append(doc.Entities, entity)
+	}
+	entity.Operations = serializer.NewOperations(&entity)
+	log.Info("fields parsed for entity:", entity.Name)
+	return entity, defErr
+}
 
-		m[entity.ID] = &entity
-		log.Info("fields parsed for entity:", entity.Name)
+// getDBType returns the declared column type, or the gorm data type with its size, precision and scale
+func getDBType(field *schema.Field) string {
+	if v, ok := field.TagSettings["TYPE"]; ok {
+		return v
+	}
+	var dataType = string(field.DataType)
+	if dataType == "" {
+		dataType = string(field.GORMDataType)
+	}
+	if dataType == "" {
+		return ""
+	}
+	switch {
+	case field.Precision > 0 && field.Scale > 0:
+		return fmt.Sprintf("%s(%d,%d)", dataType, field.Precision, field.Scale)
+	case field.Precision > 0:
+		return fmt.Sprintf("%s(%d)", dataType, field.Precision)
+	case field.Size > 0:
+		return fmt.Sprintf("%s(%d)", dataType, field.Size)
 	}
+	return dataType
+}
 
-	for idx, _ := range doc.Entities {
-		for i, _ := range doc.Entities[idx].Association {
-			if doc.Entities[idx].Association[i].EntityName != "" {
-				doc.Entities[idx].Association[i].Entity = m[doc.Entities[idx].Association[i].EntityName]
+// parseIndexes lists the indexes of a schema ordered by name, including composite ones
+func parseIndexes(s *schema.Schema) []serializer.Index {
+	var indexes []serializer.Index
+	for _, index := range s.ParseIndexes() {
+		var item = serializer.Index{
+			Name:    index.Name,
+			Unique:  index.Class == "UNIQUE",
+			Class:   index.Class,
+			Type:    index.Type,
+			Comment: index.Comment,
+		}
+		for _, option := range index.Fields {
+			if option.Field != nil {
+				item.Fields = append(item.Fields, option.DBName)
+			} else if option.Expression != "" {
+				item.Fields = append(item.Fields, option.Expression)
 			}
 		}
+		indexes = append(indexes, item)
 	}
-
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+	return indexes
 }
 
-func getJsonType(field *schema.Field) string {
-	goType := field.DataType
+func joinName(list, name string) string {
+	if list == "" {
+		return name
+	}
+	return list + "," + name
+}
 
-	switch goType {
-	case schema.String:
-		return "string"
-	case schema.Int, schema.Uint:
-		return "integer"
-	case schema.Bool:
-		return "boolean"
-	case schema.Float:
-		return "number"
-	case schema.Time:
-		return "string" // date-time could be used, but we'll keep it simple
-	case schema.Bytes:
-		return "string" // or "binary"
-	}
-	return "string"
+// describeNested fills descriptions of nested properties from the source comments of their struct types
+func describeNested(field *serializer.Field, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	t = serializer.ValueType(t)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if field.Items != nil {
+			describeNested(field.Items, t.Elem())
+		}
+	case reflect.Map:
+		if field.Values != nil {
+			describeNested(field.Values, t.Elem())
+		}
+	case reflect.Struct:
+		var comments = map[string]string{}
+		if def, err := GetStructDefinition(t); err == nil {
+			for _, item := range def.Fields {
+				if _, ok := comments[item.Name]; !ok || item.Origin == "" {
+					comments[item.Name] = item.Description
+				}
+			}
+		}
+		for i := range field.Properties {
+			var property = &field.Properties[i]
+			if property.Description == "" {
+				property.Description = comments[property.Name]
+			}
+			if sf, ok := t.FieldByName(property.Name); ok {
+				describeNested(property, sf.Type)
+			}
+		}
+	}
 }
 
 func ExtractEnumValues(input string) []string {
@@ -180,106 +273,114 @@ func ExtractEnumValues(input string) []string {
 	return values
 }
 
-func ModelDataFaker(entity *serializer.Entity) serializer.DataSample {
+// ModelDataFaker builds the samples of an entity from its first database row, or from faked
+// values when there is no database or row. A failing query is returned along with faked samples.
+func ModelDataFaker(entity *serializer.Entity, settings serializer.Settings, database *gorm.DB) (serializer.DataSample, error) {
 	log.Info("Faking data for entity:", entity.Name)
 	var sample = serializer.DataSample{}
-	sample.CreateJSON = "{"
-	sample.UpdateJSON = "{"
+	var queryErr error
 	// try get a data from database
 	var object = reflect.Indirect(reflect.New(entity.Resource.Type))
 	ptr := object.Addr().Interface()
-	if db.First(ptr).RowsAffected == 0 {
-		log.Info("Database doesn't contain any data for entity:", entity.Name)
+	var found = false
+	if database != nil && sampleFromDB(entity, settings) {
+		var result = database.Limit(1).Find(ptr)
+		if result.Error != nil {
+			queryErr = fmt.Errorf("sample of %s: %w", entity.ID, result.Error)
+		}
+		found = result.Error == nil && result.RowsAffected > 0
+	}
+	if !found {
+		log.Info("No database sample for entity:", entity.Name)
 		log.Info("Faking data using faker...")
-
-		//_ = gofakeit.Struct(ptr)
-
+		object.Set(reflect.Zero(object.Type()))
 		for _, item := range entity.Fields {
 			var field = object.FieldByName(item.Name)
-			if len(item.Enum) > 0 {
-				field.SetString(item.Enum[0])
-				continue
-			}
-			if item.GoType == "decimal.Decimal" {
-				field.Set(reflect.ValueOf(decimal.NewFromFloat(3.14)))
-				continue
-			}
-			var baseField = field
-			for field.Kind() == reflect.Ptr {
-				field = field.Elem()
-			}
-
-			switch field.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				setFieldValue(baseField, rand.Intn(1000))
-			case reflect.Float64:
-				setFieldValue(baseField, rand.Float64())
-			case reflect.Float32:
-				setFieldValue(baseField, rand.Float32())
-			case reflect.String:
-				setFieldValue(baseField, text.Random(5))
-			case reflect.Bool:
-				setFieldValue(baseField, rand.Intn(2) == 0)
-			default:
-
-			}
+			fakeField(newFaker(settings.Seed, entity.ID, item.Name), field, item)
 			log.Info("Faked data.")
 		}
+	} else {
+		protectSample(object, entity, settings.Seed)
 	}
-
-	var comment = ""
 	for _, item := range entity.Fields {
-		if item.AutoIncrement {
-			continue
-		}
-		if item.DBName == "created_at" || item.DBName == "updated_at" || item.DBName == "deleted_at" {
-			continue
-		}
-		var v, _ = json.Marshal(object.FieldByName(item.Name).Interface())
-		var description = []string{
-			item.GoType,
+		var field = object.FieldByName(item.Name)
+		if value, ok := referencedSample(entity, item); ok && field.CanSet() {
+			assignValue(field, value)
 		}
-		if len(item.Enum) > 0 {
-			description = append(description, "enum: "+strings.Join(item.Enum, ", "))
-		}
-		if item.Description != "" {
-			description = append(description, item.Description)
+	}
+
+	for i, item := range entity.Fields {
+		if field := object.FieldByName(item.Name); field.IsValid() {
+			entity.Fields[i].SampleData = field.Interface()
 		}
-		if item.Nullable {
-			description = append(description, "optional")
+	}
+	for i, primary := range entity.PrimaryKey {
+		if field := object.FieldByName(primary.Name); field.IsValid() {
+			entity.PrimaryKey[i].SampleData = field.Interface()
 		}
-		if item.Unique {
-			description = append(description, "unique")
+	}
+
+	var comments = map[string]string{}
+	var unreadable = map[string]bool{}
+	for _, item := range entity.Fields {
+		comments[item.JsonTag] = sampleComment(item)
+		unreadable[item.JsonTag] = !item.InResponse()
+		if item.DBName == "created_at" || item.DBName == "updated_at" || item.DBName == "deleted_at" {
+			continue
 		}
-		if item.Validation != "" {
-			description = append(description, "validation: "+item.Validation)
+		var property = serializer.SampleProperty{
+			Name:    item.JsonTag,
+			Value:   object.FieldByName(item.Name).Interface(),
+			Comment: comments[item.JsonTag],
 		}
-		if item.PrimaryKey {
-			description = append(description, "pk")
+		if item.InCreate() {
+			sample.Create = append(sample.Create, property)
 		}
-		if item.AutoIncrement {
-			description = append(description, "autoIncr.")
+		if item.InUpdate() {
+			sample.Update = append(sample.Update, property)
 		}
+	}
 
-		var row = fmt.Sprintf(comment+"\n\t\"%s\":%s,", item.JsonTag, string(v))
-		comment = " // " + strings.Join(description, ",")
-		sample.CreateJSON += row
-		if !item.PrimaryKey {
-			sample.UpdateJSON += row
+	if b, err := json.Marshal(object.Interface()); err == nil {
+		if response, err := serializer.NewSampleObject(b); err == nil {
+			for _, property := range response {
+				if !unreadable[property.Name] {
+					property.Comment = comments[property.Name]
+					sample.Response = append(sample.Response, property)
+				}
+			}
 		}
 	}
-
-	sample.CreateJSON = strings.Trim(sample.CreateJSON, ",") + comment + "\n}"
-	sample.UpdateJSON += strings.Trim(sample.UpdateJSON, ",") + comment + "\n}"
-	sample.BatchJSON = "[\n" + shift(sample.CreateJSON) + "\n]"
-	sample.SingleResponseJSON = text.ToJSON(object.Interface())
-	sample.MultipleResponseJSON = "[\n" + shift(sample.SingleResponseJSON) + "\n]"
-	return sample
+	return sample, queryErr
 }
 
-func shift(s string) string {
-	return "\t" + strings.Join(strings.Split(s, "\n"), "\n\t")
+// sampleComment describes a field in one line for commented samples
+func sampleComment(item serializer.Field) string {
+	var description = []string{
+		item.GoType,
+	}
+	if len(item.Enum) > 0 {
+		description = append(description, "enum: "+strings.Join(item.Enum, ", "))
+	}
+	if item.Description != "" {
+		description = append(description, strings.ReplaceAll(item.Description, "\n", " "))
+	}
+	if item.Nullable {
+		description = append(description, "optional")
+	}
+	if item.Unique {
+		description = append(description, "unique")
+	}
+	if len(item.Rules) > 0 {
+		description = append(description, "validation: "+item.Rules.Text())
+	}
+	if item.PrimaryKey {
+		description = append(description, "pk")
+	}
+	if item.AutoIncrement {
+		description = append(description, "autoIncr.")
+	}
+	return strings.Join(description, ",")
 }
 
 // Helper function to set value, handling pointers recursively
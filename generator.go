@@ -0,0 +1,101 @@
+package docify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/getevo/docify/markdown"
+	"github.com/getevo/docify/openapi"
+	"github.com/getevo/docify/postman"
+	"github.com/getevo/docify/serializer"
+	"strings"
+	"sync"
+)
+
+type Generator = serializer.Generator
+
+// Report tells which files a generator wrote, and why it failed if it did.
+type Report struct {
+	Generator string
+	Files     []string
+	Err       error
+}
+
+var generators []Generator
+var generatorsMu sync.RWMutex
+
+func init() {
+	RegisterGenerator(postman.Generator{})
+	RegisterGenerator(markdown.Generator{})
+	RegisterGenerator(openapi.Generator{})
+}
+
+// RegisterGenerator adds a generator, or replaces the one with the same name in place.
+// Generators run in registration order unless the options or project.yml list them.
+func RegisterGenerator(g Generator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	for i := range generators {
+		if generators[i].Name() == g.Name() {
+			generators[i] = g
+			return
+		}
+	}
+	generators = append(generators, g)
+}
+
+// LookupGenerator returns the generator registered with a name.
+func LookupGenerator(name string) (Generator, bool) {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	for _, g := range generators {
+		if g.Name() == name {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+// Generators returns the registered generators in registration order.
+func Generators() []Generator {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	return append([]Generator{}, generators...)
+}
+
+// Generate runs the generators selected by opts.Generators, else by the generators of
+// project.yml, else all of them. A failing generator does not stop the others; their errors
+// are joined and every generator gets a report.
+func Generate(ctx context.Context, doc *serializer.Doc, opts Options) ([]Report, error) {
+	opts = opts.WithDefaults()
+	var names = opts.GeneratorNames(doc.Settings)
+	var selected []Generator
+	var errs []error
+	if len(names) == 0 {
+		selected = Generators()
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if g, ok := LookupGenerator(name); ok {
+			selected = append(selected, g)
+		} else {
+			errs = append(errs, fmt.Errorf("unknown generator %q", name))
+		}
+	}
+
+	var reports []Report
+	for _, g := range selected {
+		if err := ctx.Err(); err != nil {
+			return reports, errors.Join(append(errs, err)...)
+		}
+		var w = serializer.NewFileWriter(opts, doc.Settings.GeneratorOptions[g.Name()])
+		var report = Report{Generator: g.Name()}
+		if err := g.Generate(doc, w); err != nil {
+			report.Err = fmt.Errorf("%s: %w", g.Name(), err)
+			errs = append(errs, report.Err)
+		}
+		report.Files = w.Files()
+		reports = append(reports, report)
+	}
+	return reports, errors.Join(errs...)
+}
@@ -8,6 +8,7 @@ require (
 	github.com/getevo/restify v0.0.0-20250227131557-921d28a20b95
 	github.com/nao1215/markdown v0.7.1
 	github.com/olekukonko/tablewriter v0.0.5
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
 	github.com/shopspring/decimal v1.4.0
 	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.25.12
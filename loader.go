@@ -0,0 +1,194 @@
+package docify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// SourceDir is the directory `go list` is executed from when resolving packages.
+// Empty means the current working directory.
+var SourceDir = ""
+
+// Package describes a Go package resolved through the module graph.
+type Package struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	Module     string
+	ModuleDir  string
+	GoFiles    []string
+}
+
+// Files returns absolute paths of the package's compiled Go files.
+func (p *Package) Files() []string {
+	var files = make([]string, 0, len(p.GoFiles))
+	for _, file := range p.GoFiles {
+		files = append(files, filepath.Join(p.Dir, file))
+	}
+	return files
+}
+
+type goListPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+	CgoFiles   []string
+	Module     *struct {
+		Path string
+		Dir  string
+	}
+	Error *struct {
+		Err string
+	}
+}
+
+var packages = map[string]*Package{}
+var packagesMu sync.Mutex
+
+// goListKey identifies a `go list` run; its result only depends on the package and directory.
+type goListKey struct {
+	pkgPath string
+	dir     string
+}
+
+// goListCall is a `go list` run, in flight until done is closed. Failures are kept as well,
+// so a package that cannot be resolved is not listed again on every lookup.
+type goListCall struct {
+	done chan struct{}
+	pkg  *Package
+	err  error
+}
+
+var goListCalls = map[goListKey]*goListCall{}
+
+// LoadPackage resolves a package import path to its source directory using `go list`,
+// which honors go.mod, replace directives, vendor/ and go.work files.
+// Results are cached per import path.
+func LoadPackage(pkgPath string) (*Package, error) {
+	return loadPackage(pkgPath, "")
+}
+
+func loadPackage(pkgPath string, hint string) (*Package, error) {
+	packagesMu.Lock()
+	pkg, ok := packages[pkgPath]
+	packagesMu.Unlock()
+	if ok {
+		return pkg, nil
+	}
+
+	var dirs = []string{SourceDir}
+	if hint != "" {
+		dirs = append(dirs, hint)
+	}
+	var err error
+	for _, dir := range dirs {
+		pkg, err = cachedGoList(pkgPath, dir)
+		if err == nil {
+			packagesMu.Lock()
+			packages[pkgPath] = pkg
+			packagesMu.Unlock()
+			return pkg, nil
+		}
+	}
+	return nil, err
+}
+
+// cachedGoList runs `go list` once per package and directory. The lock is not held while
+// the command runs; concurrent lookups of the same key wait for the running call instead.
+func cachedGoList(pkgPath, dir string) (*Package, error) {
+	var key = goListKey{pkgPath: pkgPath, dir: dir}
+	packagesMu.Lock()
+	call, ok := goListCalls[key]
+	if !ok {
+		call = &goListCall{done: make(chan struct{})}
+		goListCalls[key] = call
+	}
+	packagesMu.Unlock()
+
+	if ok {
+		<-call.done
+		return call.pkg, call.err
+	}
+	call.pkg, call.err = goList(pkgPath, dir)
+	close(call.done)
+	return call.pkg, call.err
+}
+
+func goList(pkgPath, dir string) (*Package, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-e", "-find", "-json", pkgPath)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go list %s: %s", pkgPath, strings.TrimSpace(stderr.String()))
+	}
+
+	var result goListPackage
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("go list %s: %w", pkgPath, err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("go list %s: %s", pkgPath, result.Error.Err)
+	}
+	if result.Dir == "" {
+		return nil, fmt.Errorf("go list %s: package directory not found", pkgPath)
+	}
+
+	var pkg = Package{
+		ImportPath: result.ImportPath,
+		Name:       result.Name,
+		Dir:        result.Dir,
+		GoFiles:    append(result.GoFiles, result.CgoFiles...),
+	}
+	if result.Module != nil {
+		pkg.Module = result.Module.Path
+		pkg.ModuleDir = result.Module.Dir
+	}
+	return &pkg, nil
+}
+
+// typeSourceDir returns the directory of a source file declaring one of the
+// type's methods, as recorded in the binary. It lets go list run inside the
+// right module even when the binary is launched from elsewhere.
+func typeSourceDir(t reflect.Type) string {
+	for _, typ := range []reflect.Type{t, reflect.PointerTo(t)} {
+		for i := 0; i < typ.NumMethod(); i++ {
+			fn := runtime.FuncForPC(typ.Method(i).Func.Pointer())
+			if fn == nil {
+				continue
+			}
+			file, _ := fn.FileLine(fn.Entry())
+			if file == "" || strings.HasPrefix(file, "<") {
+				continue
+			}
+			dir := filepath.Dir(file)
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// relativePath returns path relative to the working directory when possible.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
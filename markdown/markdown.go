@@ -1,14 +1,13 @@
 package markdown
 
 import (
-	"github.com/getevo/evo/v2/lib/log"
 	"fmt"
 	"github.com/getevo/docify/serializer"
+	"github.com/getevo/evo/v2/lib/log"
 
-	"github.com/getevo/evo/v2/lib/gpath"
 	md "github.com/nao1215/markdown"
 	"github.com/olekukonko/tablewriter"
-	"os"
+	"io"
 	"strings"
 )
 
@@ -33,12 +32,22 @@ func (a Attributes) Render() string {
 	return "`" + strings.Join(a, "`  `") + "`"
 }
 
-func Generate(project *serializer.Doc) {
-	_ = gpath.MakePath("./docify")
-	// Open the file with create/truncate mode (overwrite if it exists)
-	file, err := os.OpenFile("./docify/readme.md", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+// Generator writes readme.md and a page per entity.
+type Generator struct{}
+
+func (Generator) Name() string {
+	return "markdown"
+}
+
+// Generate writes readme.md and a page per entity to the output directory.
+func Generate(project *serializer.Doc, opts serializer.Options) error {
+	return Generator{}.Generate(project, serializer.NewFileWriter(opts, project.Settings.GeneratorOptions["markdown"]))
+}
+
+func (Generator) Generate(project *serializer.Doc, w serializer.Writer) error {
+	file, err := w.Create("readme.md")
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer file.Close()
 
@@ -55,29 +64,40 @@ func Generate(project *serializer.Doc) {
 	var links []string
 
 	for _, item := range project.Entities {
-		var path = "./docify/" + item.Pkg + "." + item.Name + ".md"
-		GenerateEntityDoc(path, item)
+		if err := generateEntityFile(w, item, project.Settings); err != nil {
+			return fmt.Errorf("%s: %w", item.ID, err)
+		}
 		links = append(links, md.Link(item.Pkg+"."+item.Name, item.Pkg+"."+item.Name+".md"))
 	}
 	doc.BulletList(links...)
 
-	doc.LF()
-	doc.H2("Postman Collections")
-	doc.PlainText(md.Link("Download Restify Collection", "./restify.json"))
-
-	err = doc.Build()
-	if err != nil {
-		panic(err)
+	var opts = w.Options()
+	if opts.Runs(project.Settings, "postman") {
+		doc.LF()
+		doc.H2("Postman Collections")
+		doc.PlainText(md.Link("Download Restify Collection", "./restify.json"))
 	}
+	if opts.Runs(project.Settings, "openapi") {
+		doc.LF()
+		doc.H2("OpenAPI Specification")
+		doc.BulletList(md.Link("openapi.yaml", "./openapi.yaml"), md.Link("openapi.json", "./openapi.json"))
+	}
+
+	return doc.Build()
 }
 
-func GenerateEntityDoc(path string, entity serializer.Entity) {
-	log.Info("Postman Entity: " + entity.Name)
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+func generateEntityFile(w serializer.Writer, entity serializer.Entity, settings serializer.Settings) error {
+	file, err := w.Create(entity.Pkg + "." + entity.Name + ".md")
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer file.Close()
+	return GenerateEntityDoc(file, entity, settings)
+}
+
+// GenerateEntityDoc writes the page of an entity.
+func GenerateEntityDoc(file io.Writer, entity serializer.Entity, settings serializer.Settings) error {
+	log.Info("Markdown Entity: " + entity.Name)
 
 	// Initialize markdown writer
 	doc := md.NewMarkdown(file)
@@ -93,7 +113,11 @@ func GenerateEntityDoc(path string, entity serializer.Entity) {
 	doc.LF()
 
 	doc.H2("Definition")
-	doc.CodeBlocks(md.SyntaxHighlightGo, entity.Definition.Body)
+	if settings.ExpandEmbedded && entity.Definition.ExpandedBody != "" {
+		doc.CodeBlocks(md.SyntaxHighlightGo, entity.Definition.ExpandedBody)
+	} else {
+		doc.CodeBlocks(md.SyntaxHighlightGo, entity.Definition.Body)
+	}
 
 	doc.PlainText(Br)
 
@@ -103,10 +127,20 @@ func GenerateEntityDoc(path string, entity serializer.Entity) {
 		Header: []string{"Name", "Data Type", "Specifications", "Validation", "Description"},
 	}
 	for _, param := range entity.Fields {
+		if param.Visibility.Hidden {
+			continue
+		}
 		var attributes Attributes
 		if param.PrimaryKey {
 			attributes.Add("Primary Key")
 		}
+		if param.ReadOnly() {
+			attributes.Add("Read Only")
+		} else if param.WriteOnly() {
+			attributes.Add("Write Only")
+		} else if param.InCreate() && !param.InUpdate() && !param.PrimaryKey {
+			attributes.Add("Cannot be updated")
+		}
 
 		if param.Nullable {
 			attributes.Add("Accepts Null")
@@ -123,36 +157,136 @@ func GenerateEntityDoc(path string, entity serializer.Entity) {
 		if param.Indexed {
 			attributes.Add("Indexed")
 		}
+		if param.Index != "" {
+			attributes.Add("Index: " + param.Index)
+		}
+		if param.DBType != "" {
+			attributes.Add("DB Type: " + param.DBType)
+		}
 		if len(param.Enum) > 0 {
 			attributes.Add(fmt.Sprintf("Enum: %s", strings.Join(param.Enum, ",")))
 		}
 		if param.Default != "" {
 			attributes.Add(fmt.Sprintf("Default Value: %s", param.Default))
 		}
+		if param.Sensitive != "" {
+			attributes.Add("Sensitive")
+		}
 
 		var row = []string{
 			param.JsonTag,
-			param.JsonType,
+			param.TypeName(),
 			attributes.Render(),
-			param.Validation,
-			param.Description,
+			param.Rules.Text(),
+			strings.ReplaceAll(param.Description, "\n", "<br>"),
 		}
 		tb.Rows = append(tb.Rows, row)
+		tb.Rows = append(tb.Rows, nestedRows(param.JsonTag, param)...)
 	}
 
 	doc.PlainText(GetTable(tb))
 
+	for _, param := range entity.Fields {
+		if len(param.EnumValues) == 0 || param.Visibility.Hidden {
+			continue
+		}
+		doc.H4("Enum: " + param.JsonTag)
+		var tb = md.TableSet{
+			Header: []string{"Value", "Constant", "Description"},
+		}
+		for _, item := range param.EnumValues {
+			tb.Rows = append(tb.Rows, []string{
+				"`" + item.Value + "`",
+				item.Name,
+				strings.ReplaceAll(item.Description, "\n", "<br>"),
+			})
+		}
+		doc.PlainText(GetTable(tb))
+	}
+
+	if len(entity.PrimaryKey) > 1 {
+		var keys []string
+		for _, key := range entity.PrimaryKey {
+			keys = append(keys, "`"+key.DBName+"`")
+		}
+		doc.H2("Primary Key")
+		doc.PlainText("> Composite primary key: " + strings.Join(keys, ", "))
+		doc.LF()
+	}
+
+	if len(entity.Indexes) > 0 {
+		doc.H2("Indexes")
+		var tb = md.TableSet{
+			Header: []string{"Name", "Fields", "Unique", "Type", "Comment"},
+		}
+		for _, index := range entity.Indexes {
+			var unique = "No"
+			if index.Unique {
+				unique = "Yes"
+			}
+			var kind = index.Class
+			if index.Type != "" {
+				kind = strings.TrimSpace(kind + " " + index.Type)
+			}
+			tb.Rows = append(tb.Rows, []string{
+				index.Name,
+				strings.Join(index.Fields, ", "),
+				unique,
+				kind,
+				index.Comment,
+			})
+		}
+		doc.PlainText(GetTable(tb))
+	}
+
+	if len(entity.Association) > 0 {
+		doc.H2("Associations")
+		var tb = md.TableSet{
+			Header: []string{"Name", "Entity", "Relation", "Foreign Key", "References", "Join Table"},
+		}
+		for _, assoc := range entity.Association {
+			var foreignKeys, references []string
+			for _, key := range assoc.Keys {
+				foreignKeys = append(foreignKeys, key.ForeignKey)
+				if key.References != "" {
+					references = append(references, key.References)
+				} else {
+					references = append(references, "'"+key.Value+"'")
+				}
+			}
+			tb.Rows = append(tb.Rows, []string{
+				assoc.JsonTag,
+				associationLink(assoc),
+				assoc.Relation(),
+				strings.Join(foreignKeys, ", "),
+				strings.Join(references, ", "),
+				assoc.JoinTable,
+			})
+		}
+		doc.PlainText(GetTable(tb))
+	}
+
 	doc.H2("APIs:")
 
-	for _, item := range entity.Endpoints {
+	for _, op := range entity.Operations {
 		doc.PlainText("<details>")
-		doc.PlainTextf("<summary><code>%s</code> <code><b>%s</b></code> <code>%s</code> <code>%s</code></summary>\n", item.Method, item.AbsoluteURI, item.Name, item.Description)
+		doc.PlainTextf("<summary><code>%s</code> <code><b>%s</b></code> <code>%s</code> <code>%s</code></summary>\n", op.Method, op.Path, op.Name, op.Description)
+		if len(op.PathParams) > 0 {
+			doc.H5("Path Parameters")
+			var tb = md.TableSet{
+				Header: []string{"Name", "Data Type", "Description"},
+			}
+			for _, param := range op.PathParams {
+				tb.Rows = append(tb.Rows, []string{param.Name, param.Type, param.Description})
+			}
+			doc.PlainText(GetTable(tb))
+		}
 		doc.H5("Parameters")
 
-		if item.AcceptData {
-			doc.PlainText("> Accepts: `application/json`,`application/x-www-form-urlencoded`,`multipart/form-data`")
+		if op.Body != nil {
+			doc.PlainText("> Accepts: `" + strings.Join(op.Body.ContentTypes, "`,`") + "`")
 			var p = "\n> "
-			if item.Batch {
+			if op.Body.Batch {
 				p += md.Link("[]"+entity.Name, "#fields") + " (Array of Objects)"
 			} else {
 				p += md.Link(entity.Name, "#fields") + " (Object)"
@@ -163,84 +297,78 @@ func GenerateEntityDoc(path string, entity serializer.Entity) {
 
 			doc.PlainTextf("<summary><code>JSON Example</code></summary>\r\n")
 			var body = ""
-			if item.Batch {
-				body = entity.DataSample.BatchJSON
+			var form = ""
+			if op.Body.Batch {
+				body = entity.DataSample.Batch().CommentedJSON()
 			} else {
-				if item.Method == "PUT" {
-					body = entity.DataSample.CreateJSON
-				} else {
-					body = entity.DataSample.UpdateJSON
+				var sample = entity.DataSample.Update
+				if op.Body.Variant == serializer.BodyCreate {
+					sample = entity.DataSample.Create
 				}
-
+				body = sample.CommentedJSON()
+				form = sample.Encode()
 			}
 			doc.CodeBlocks(md.SyntaxHighlightJavaScript, body)
+			if form != "" {
+				doc.PlainTextf("<summary><code>Form Example</code></summary>\r\n")
+				doc.CodeBlocks(md.SyntaxHighlightNone, form)
+			}
 
 		} else {
 			doc.PlainText("> None")
 		}
 		doc.LF()
 		doc.H5("Query Parameters")
-		if item.Filterable {
-			doc.PlainTextf("\n> Filters: %s", md.Link("Filters Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#query-parameters-explanation"))
-			if item.Method == "GET" {
-				doc.PlainTextf("\n> Offset, Limit and Pagination: %s", md.Link("Pagination Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#offset-and-limit"))
-				doc.PlainTextf("\n> Limiting fields: %s", md.Link("Select Specific Fields Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#select-specific-fields"))
-				doc.PlainTextf("\n> Aggregations: %s", md.Link("Aggregation Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#aggregation"))
-				if len(entity.Association) > 0 {
-					var tb = md.TableSet{
-						Header: []string{"Assoc. Query Parameter", "Data Type", "Type", "Example"},
-					}
-					for _, assoc := range entity.Association {
-						if assoc.Entity == nil {
-							continue
-						}
-						var t = "Object"
-						if assoc.Array {
-							t = "Array of Objects"
-						}
-						tb.Rows = append(tb.Rows, []string{
-							assoc.Name,
-							md.Link(assoc.Entity.Pkg+"."+assoc.Entity.Name, "./"+assoc.Entity.Pkg+"."+assoc.Entity.Name+".md"),
-							t,
-							fmt.Sprintf("%s?associations=%s", item.AbsoluteURI, assoc.Name),
-						})
-
+		var params = md.TableSet{
+			Header: []string{"Name", "Data Type", "Example", "Description"},
+		}
+		var filters []string
+		for _, param := range op.QueryParams {
+			if param.Style == "deepObject" {
+				filters = append(filters, "`"+param.Name+"`")
+				continue
+			}
+			params.Rows = append(params.Rows, []string{param.Name, param.Type, "`" + param.Example + "`", param.Description})
+		}
+		doc.PlainText(GetTable(params))
+		if len(filters) > 0 {
+			doc.PlainTextf("\n> Filters: %s as `field[op]=value` where op is one of `%s`. %s", strings.Join(filters, ", "), strings.Join(serializer.FilterOperators, "`, `"), md.Link("Filters Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#query-parameters-explanation"))
+		}
+		if op.Method == "GET" && op.Endpoint.Filterable {
+			doc.PlainTextf("\n> Offset, Limit and Pagination: %s", md.Link("Pagination Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#offset-and-limit"))
+			doc.PlainTextf("\n> Limiting fields: %s", md.Link("Select Specific Fields Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#select-specific-fields"))
+			doc.PlainTextf("\n> Aggregations: %s", md.Link("Aggregation Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#aggregation"))
+			if len(entity.Association) > 0 {
+				var tb = md.TableSet{
+					Header: []string{"Assoc. Query Parameter", "Data Type", "Type", "Relation", "Example"},
+				}
+				for _, assoc := range entity.Association {
+					var t = "Object"
+					if assoc.Array {
+						t = "Array of Objects"
 					}
+					tb.Rows = append(tb.Rows, []string{
+						assoc.Name,
+						associationLink(assoc),
+						t,
+						assoc.Relation(),
+						fmt.Sprintf("%s?associations=%s", op.Path, assoc.Name),
+					})
 
-					doc.PlainTextf("\n> Loading Associations: %s", md.Link("Associations Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#loading-associations"))
-					doc.PlainTextf("\n")
-					doc.Table(tb)
 				}
-			}
 
-		} else {
-			doc.PlainText("> None")
+				doc.PlainTextf("\n> Loading Associations: %s", md.Link("Associations Guide", "https://github.com/getevo/restify/blob/master/docs/endpoints.md#loading-associations"))
+				doc.PlainTextf("\n")
+				doc.Table(tb)
+			}
 		}
 
 		doc.H5("Response")
 		var tb = md.TableSet{
 			Header: []string{"Status Code", "Content Type", "Response Type", "Data"},
 		}
-		var body = ""
-
-		if item.Batch {
-			body += md.Link("[]"+entity.Name, "#fields") + " (Array of Objects)"
-		} else {
-			body += md.Link(entity.Name, "#fields") + " (Object)"
-		}
-
-		if item.Method == "DELETE" {
-			body += "No Content"
-		}
-
-		tb.Rows = [][]string{
-			{"200", "application/json", "Success", body},
-			{"400", "application/json", "Validation Error", md.Link("Validation Guide", "https://github.com/getevo/restify/blob/master/docs/developer.md#validation-in-restify")},
-			{"400", "application/json", "Bad Request"},
-			{"401", "application/json", "Unauthorized"},
-			{"403", "application/json", "Forbidden"},
-			{"404", "application/json", "Not Found"},
-			{"500", "application/json", "Internal Server Error"},
+		for _, response := range op.Responses {
+			tb.Rows = append(tb.Rows, []string{response.Status, serializer.ContentTypeJSON, response.Description, responseData(entity, response)})
 		}
 		doc.PlainText(GetTable(tb))
 
@@ -250,10 +378,66 @@ func GenerateEntityDoc(path string, entity serializer.Entity) {
 		doc.PlainText(Br)
 	}
 
-	err = doc.Build()
-	if err != nil {
-		panic(err)
+	return doc.Build()
+}
+
+// nestedRows lists the properties of object, array and map fields using dotted paths
+func nestedRows(prefix string, field serializer.Field) [][]string {
+	var rows [][]string
+	switch {
+	case field.Items != nil:
+		rows = append(rows, nestedRows(prefix+"[]", *field.Items)...)
+	case field.Values != nil:
+		rows = append(rows, nestedRows(prefix+".{key}", *field.Values)...)
+	}
+	for _, property := range field.Properties {
+		var path = prefix + "." + property.JsonTag
+		var attributes Attributes
+		if property.Nullable {
+			attributes.Add("Accepts Null")
+		}
+		rows = append(rows, []string{
+			path,
+			property.TypeName(),
+			attributes.Render(),
+			"",
+			strings.ReplaceAll(property.Description, "\n", "<br>"),
+		})
+		rows = append(rows, nestedRows(path, property)...)
+	}
+	return rows
+}
+
+// responseData describes the data returned by a response
+func responseData(entity serializer.Entity, response serializer.Response) string {
+	switch response.Kind {
+	case serializer.ResponseSingle:
+		return md.Link(entity.Name, "#fields") + " (Object)"
+	case serializer.ResponseList, serializer.ResponseBatchCreate:
+		return md.Link("[]"+entity.Name, "#fields") + " (Array of Objects)"
+	case serializer.ResponseBatchUpdate:
+		return md.Link(entity.Name, "#fields") + " (Object of the applied values), or " +
+			md.Link("[]"+entity.Name, "#fields") + " (Array of updated Objects) with ?return=1"
+	case serializer.ResponsePaginated:
+		return md.Link("[]"+entity.Name, "#fields") + " (Array of Objects, paginated)"
+	case serializer.ResponseDelete:
+		return "Response envelope without data"
+	case serializer.ResponseAggregate:
+		return "Aggregation result"
+	case serializer.ResponseModelInfo:
+		return "Model information"
+	case serializer.ResponseValidationError:
+		return md.Link("Validation Guide", "https://github.com/getevo/restify/blob/master/docs/developer.md#validation-in-restify")
+	}
+	return ""
+}
+
+// associationLink links to the associated entity document, or names it when it is not documented
+func associationLink(assoc serializer.Association) string {
+	if assoc.Entity == nil {
+		return assoc.EntityName
 	}
+	return md.Link(assoc.Entity.Pkg+"."+assoc.Entity.Name, "./"+assoc.Entity.Pkg+"."+assoc.Entity.Name+".md")
 }
 
 func GetTable(tb md.TableSet) string {
@@ -1,29 +1,61 @@
 package openapi
 
 import (
+	"errors"
+	"fmt"
+	"github.com/getevo/docify/serializer"
 	"github.com/getevo/evo/v2/lib/gpath"
-	"github.com/getevo/evo/v2/lib/log"
 	"gopkg.in/yaml.v3"
 	"os"
 )
 
-func Initialize() *OpenAPI {
-	var obj OpenAPI
-	var filename = "docify/openapi.yml"
+// Initialize builds the OpenAPI document of the restify endpoints of the doc and merges the
+// hand-written openapi.yml of the output directory over it, when there is one, so its paths,
+// descriptions, examples and components override or extend the generated ones.
+func Initialize(doc *serializer.Doc, opts serializer.Options) (*OpenAPI, error) {
+	var obj = OpenAPI{OpenAPI: "3.0.3"}
+	obj.Info.Title = doc.Title
+	obj.Info.Description = doc.Description
+	obj.Info.Version = "1.0.0"
+	var err = obj.ParseRestify(doc)
+
+	var filename = opts.Output("openapi.yml")
 	if gpath.IsFileExist(filename) {
-		file, err := os.ReadFile(filename)
-		if err != nil {
-			log.Fatal(err)
-			return nil
+		file, readErr := os.ReadFile(filename)
+		if readErr != nil {
+			return nil, readErr
 		}
-
-		err = yaml.Unmarshal(file, &obj)
-		if err != nil {
-			log.Fatal(err)
-			return nil
+		var base OpenAPI
+		if readErr = yaml.Unmarshal(file, &base); readErr != nil {
+			return nil, fmt.Errorf("parse %s: %w", filename, readErr)
 		}
+		obj.Merge(&base)
+	}
+	return &obj, err
+}
+
+// Generator writes the OpenAPI document of the restify endpoints as openapi.yaml and openapi.json.
+type Generator struct{}
 
+func (Generator) Name() string {
+	return "openapi"
+}
+
+func (Generator) Generate(doc *serializer.Doc, w serializer.Writer) error {
+	obj, err := Initialize(doc, w.Options())
+	if obj == nil {
+		return err
+	}
+	b, yamlErr := obj.GenerateYaml()
+	if yamlErr != nil {
+		return errors.Join(err, yamlErr)
+	}
+	if yamlErr = w.Write("openapi.yaml", b); yamlErr != nil {
+		return errors.Join(err, yamlErr)
+	}
+	b, jsonErr := obj.GenerateJSON()
+	if jsonErr != nil {
+		return errors.Join(err, jsonErr)
 	}
-	obj.ParseRestify()
-	return &obj
+	return errors.Join(err, w.Write("openapi.json", b))
 }
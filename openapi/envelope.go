@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"github.com/getevo/docify/serializer"
+)
+
+// Component names of the restify response envelope, shared by all entities.
+const (
+	EnvelopeSchema        = "restify.Response"
+	ErrorSchema           = "restify.Error"
+	ValidationErrorSchema = "restify.ValidationError"
+	ValidationFailSchema  = "restify.ValidationErrorResponse"
+)
+
+// envelopeSchemas adds the restify envelope, written around every response, and its error
+// payloads. The envelope holds the fields restify always sends; paginated responses fill the
+// same total, offset, total_pages, current_page and size fields.
+func (o *OpenAPI) envelopeSchemas() {
+	if _, ok := o.Components.Schema(EnvelopeSchema); ok {
+		return
+	}
+	o.Components.AddSchema(ValidationErrorSchema, Schema{
+		Type:        "object",
+		Description: "validation error of a field",
+		Properties: []SchemaProperty{
+			{Name: "field", Schema: Schema{Type: "string", Description: "json name of the field"}},
+			{Name: "error", Schema: Schema{Type: "string", Description: "error message"}},
+		},
+	})
+	o.Components.AddSchema(EnvelopeSchema, Schema{
+		Type:        "object",
+		Description: "envelope of restify responses, data holds the result",
+		Properties: []SchemaProperty{
+			{Name: "total", Schema: Schema{Type: "integer", Format: "int64", Description: "total number of records"}},
+			{Name: "offset", Schema: Schema{Type: "integer", Description: "number of skipped records"}},
+			{Name: "total_pages", Schema: Schema{Type: "integer", Description: "total number of pages"}},
+			{Name: "current_page", Schema: Schema{Type: "integer", Description: "current page, starting at 1"}},
+			{Name: "size", Schema: Schema{Type: "integer", Description: "number of records per page"}},
+			{Name: "success", Schema: Schema{Type: "boolean", Description: "false when the request failed"}},
+			{Name: "error", Schema: Schema{Type: "string", Description: "error message of a failed request"}},
+			{Name: "type", Schema: Schema{Type: "string"}},
+			{Name: "validation_error", Schema: Schema{Type: "array", Items: SchemaRef(ValidationErrorSchema), Nullable: true}},
+		},
+		Required: []string{"total", "offset", "total_pages", "current_page", "size", "success", "error", "type", "validation_error"},
+	})
+	o.Components.AddSchema(ErrorSchema, envelope("failed request, success is false and error holds the message",
+		&Schema{Type: "integer", Description: "always 0"}))
+	o.Components.AddSchema(ValidationFailSchema, envelope("failed validation, validation_error lists the invalid fields",
+		&Schema{Type: "integer", Description: "always 0"}))
+}
+
+// envelope wraps data in the restify envelope; without data the data key is omitted.
+func envelope(description string, data *Schema) Schema {
+	var s = Schema{Description: description, AllOf: []*Schema{SchemaRef(EnvelopeSchema)}}
+	if data != nil {
+		s.AllOf = append(s.AllOf, &Schema{
+			Type:       "object",
+			Properties: []SchemaProperty{{Name: "data", Schema: *data}},
+			Required:   []string{"data"},
+		})
+	}
+	return s
+}
+
+// responseSchema returns the envelope of a response according to its kind, adding the
+// component of the entity envelope once.
+func (o *OpenAPI) responseSchema(entity *serializer.Entity, response serializer.Response) *Schema {
+	o.envelopeSchemas()
+	var object = SchemaRef(SchemaName(entity))
+	var name = SchemaName(entity)
+	var s Schema
+	switch response.Kind {
+	case serializer.ResponseSingle:
+		name += "Response"
+		s = envelope("single "+entity.ID, object)
+	case serializer.ResponseList:
+		name += "List"
+		s = envelope("list of "+entity.ID, &Schema{Type: "array", Items: object})
+	case serializer.ResponsePaginated:
+		name += "Page"
+		s = envelope("page of "+entity.ID, &Schema{Type: "array", Items: object})
+	case serializer.ResponseBatchCreate:
+		name += "BatchCreateResponse"
+		s = envelope("created records of "+entity.ID, &Schema{Type: "array", Items: object})
+	case serializer.ResponseBatchUpdate:
+		name += "BatchUpdateResponse"
+		s = envelope("batch update of "+entity.ID, &Schema{
+			Description: "the applied values, or the updated records with ?return=1",
+			OneOf:       []*Schema{object, {Type: "array", Items: object}},
+		})
+	case serializer.ResponseDelete:
+		return SchemaRef(EnvelopeSchema)
+	case serializer.ResponseAggregate:
+		var result = envelope("aggregated values", &Schema{Type: "array", Items: &Schema{Type: "object"}})
+		return &result
+	case serializer.ResponseModelInfo:
+		var result = envelope("model description", &Schema{Type: "object"})
+		return &result
+	case serializer.ResponseValidationError:
+		return SchemaRef(ValidationFailSchema)
+	case serializer.ResponseError:
+		return SchemaRef(ErrorSchema)
+	default:
+		return nil
+	}
+	if _, ok := o.Components.Schema(name); !ok {
+		o.Components.AddSchema(name, s)
+	}
+	return SchemaRef(name)
+}
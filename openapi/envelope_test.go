@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+// propertyNames returns the property names of a schema and of the schemas it combines.
+func propertyNames(obj *OpenAPI, s *Schema) map[string]bool {
+	var names = map[string]bool{}
+	if s.Ref != "" {
+		var target, _ = obj.Components.Schema(strings.TrimPrefix(s.Ref, "#/components/schemas/"))
+		return propertyNames(obj, target)
+	}
+	for _, property := range s.Properties {
+		names[property.Name] = true
+	}
+	for _, item := range s.AllOf {
+		for name := range propertyNames(obj, item) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func TestResponseEnvelopes(t *testing.T) {
+	var obj = OpenAPI{OpenAPI: "3.0.3"}
+	obj.Info.Title, obj.Info.Version = "Items", "1.0.0"
+	if err := obj.ParseRestify(itemDoc(t)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.GenerateYaml(); err != nil {
+		t.Fatal(err)
+	}
+
+	var envelope = []string{"total", "offset", "total_pages", "current_page", "size", "success", "error", "type", "validation_error"}
+	var tests = []struct {
+		path, method, status string
+		ref                  string
+		data                 bool
+	}{
+		{"/items/get/{id}", "GET", "200", "#/components/schemas/app.ItemResponse", true},
+		{"/items/all", "GET", "200", "#/components/schemas/app.ItemList", true},
+		{"/items/paginate", "GET", "200", "#/components/schemas/app.ItemPage", true},
+		{"/items/batch", "PUT", "200", "#/components/schemas/app.ItemBatchCreateResponse", true},
+		{"/items/batch", "PATCH", "200", "#/components/schemas/app.ItemBatchUpdateResponse", true},
+		{"/items/delete/{id}", "DELETE", "200", "#/components/schemas/" + EnvelopeSchema, false},
+		{"/items/create", "PUT", "412", "#/components/schemas/" + ValidationFailSchema, true},
+		{"/items/get/{id}", "GET", "404", "#/components/schemas/" + ErrorSchema, true},
+	}
+	for _, test := range tests {
+		var op = obj.Paths.Find(test.path).Operation(test.method)
+		var response *Response
+		for i := range op.Responses {
+			if op.Responses[i].StatusCode == test.status {
+				response = &op.Responses[i]
+			}
+		}
+		if response == nil || len(response.Content) != 1 {
+			t.Errorf("%s %s: no %s response body", test.method, test.path, test.status)
+			continue
+		}
+		var schema = response.Content[0].Schema
+		if schema.Ref != test.ref {
+			t.Errorf("%s %s %s: schema %s, want %s", test.method, test.path, test.status, schema.Ref, test.ref)
+			continue
+		}
+		var names = propertyNames(&obj, schema)
+		for _, name := range envelope {
+			if !names[name] {
+				t.Errorf("%s: envelope field %s is missing", test.ref, name)
+			}
+		}
+		if names["data"] != test.data {
+			t.Errorf("%s: data present = %v, want %v", test.ref, names["data"], test.data)
+		}
+		// restify never sends the fields of its local paginator
+		for _, name := range []string{"records", "pages", "limit", "first", "last", "page_range"} {
+			if names[name] {
+				t.Errorf("%s: documents %s which restify does not send", test.ref, name)
+			}
+		}
+		if len(names) != len(envelope)+map[bool]int{true: 1, false: 0}[test.data] {
+			t.Errorf("%s: unexpected fields %v", test.ref, names)
+		}
+	}
+}
+
+func TestBatchResponseData(t *testing.T) {
+	var obj = OpenAPI{OpenAPI: "3.0.3"}
+	obj.Info.Title, obj.Info.Version = "Items", "1.0.0"
+	if err := obj.ParseRestify(itemDoc(t)); err != nil {
+		t.Fatal(err)
+	}
+	var data = func(name string) Schema {
+		var s, ok = obj.Components.Schema(name)
+		if !ok || len(s.AllOf) != 2 {
+			t.Fatalf("%s is not an envelope with data: %+v", name, s)
+		}
+		return s.AllOf[1].Properties[0].Schema
+	}
+
+	// batch create always answers with the created records
+	if created := data("app.ItemBatchCreateResponse"); created.Type != "array" || created.Nullable || created.Items.Ref != "#/components/schemas/app.Item" {
+		t.Errorf("batch create data: %+v", created)
+	}
+	// batch update answers with the parsed values, or the updated records with ?return=1
+	var updated = data("app.ItemBatchUpdateResponse")
+	if len(updated.OneOf) != 2 || updated.OneOf[0].Ref != "#/components/schemas/app.Item" || updated.OneOf[1].Type != "array" {
+		t.Errorf("batch update data: %+v", updated)
+	}
+}
@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strconv"
+)
+
+// GenerateJSON returns the document as JSON, with the keys in the order of the YAML document.
+func (o *OpenAPI) GenerateJSON() ([]byte, error) {
+	b, err := o.GenerateYaml()
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = writeJSON(&buf, &node); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err = json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// writeJSON writes a decoded YAML node as JSON, scalars keep the type YAML resolved for them
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(node.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+			return nil
+		case "!!bool":
+			if v, err := strconv.ParseBool(node.Value); err == nil {
+				buf.WriteString(strconv.FormatBool(v))
+				return nil
+			}
+		case "!!int", "!!float":
+			if json.Valid([]byte(node.Value)) {
+				buf.WriteString(node.Value)
+				return nil
+			}
+		}
+		value, _ := json.Marshal(node.Value)
+		buf.Write(value)
+	default:
+		return fmt.Errorf("unsupported yaml node at line %d", node.Line)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"sync"
+)
+
+// The official schemas of OpenAPI documents, as published on spec.openapis.org:
+// 3.0 is https://spec.openapis.org/oas/3.0/schema/2021-09-28 and
+// 3.1 is https://spec.openapis.org/oas/3.1/schema/2022-10-07.
+//
+//go:embed schemas/openapi-3.0.json schemas/openapi-3.1.json
+var officialSchemas embed.FS
+
+var (
+	compiledSchemas   = map[string]*jsonschema.Schema{}
+	compiledSchemasMu sync.Mutex
+)
+
+// SchemaError is returned when a document does not match the official OpenAPI schema of its version.
+type SchemaError struct {
+	Version string
+	Err     *jsonschema.ValidationError
+}
+
+func (e *SchemaError) Error() string {
+	var problems []string
+	for _, cause := range leafErrors(e.Err) {
+		problems = append(problems, cause.Error())
+	}
+	return fmt.Sprintf("OpenAPI document does not match the %s schema: %s", e.Version, strings.Join(problems, "; "))
+}
+
+// leafErrors returns the innermost causes of a validation error, which name the failing keyword.
+func leafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var list []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		list = append(list, leafErrors(cause)...)
+	}
+	return list
+}
+
+// ValidateSchema validates a document against the official JSON schema of its OpenAPI version,
+// 3.0 or 3.1. The 3.1 schema leaves Schema Objects to the JSON Schema dialect; CheckStructure
+// covers their keywords for both versions.
+func ValidateSchema(doc *yaml.Node) error {
+	var root = doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	var version = "3.0"
+	if strings.HasPrefix(scalar(root, "openapi"), "3.1.") {
+		version = "3.1"
+	}
+	schema, err := officialSchema(version)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = writeJSON(&buf, doc); err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(&buf)
+	if err != nil {
+		return err
+	}
+	if err = schema.Validate(instance); err != nil {
+		if v, ok := err.(*jsonschema.ValidationError); ok {
+			return &SchemaError{Version: version, Err: v}
+		}
+		return err
+	}
+	return nil
+}
+
+// officialSchema compiles the embedded schema of a version once.
+func officialSchema(version string) (*jsonschema.Schema, error) {
+	compiledSchemasMu.Lock()
+	defer compiledSchemasMu.Unlock()
+	if schema, ok := compiledSchemas[version]; ok {
+		return schema, nil
+	}
+	var path = "schemas/openapi-" + version + ".json"
+	f, err := officialSchemas.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	document, err := jsonschema.UnmarshalJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var compiler = jsonschema.NewCompiler()
+	if err = compiler.AddResource(path, document); err != nil {
+		return nil, err
+	}
+	schema, err := compiler.Compile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	compiledSchemas[version] = schema
+	return schema, nil
+}
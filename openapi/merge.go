@@ -0,0 +1,241 @@
+package openapi
+
+import "gopkg.in/yaml.v3"
+
+// Merge overlays a hand-written document on the generated one. Values set in the overlay win:
+// its info, servers and security replace the generated ones, its tags, paths, operations,
+// parameters, bodies, responses and components override those with the same key and the
+// others are added, so custom endpoints can be documented next to the restify ones. Fields
+// that are not modeled, such as components.responses or x- extensions, are merged by key.
+func (o *OpenAPI) Merge(overlay *OpenAPI) {
+	if overlay.OpenAPI != "" {
+		o.OpenAPI = overlay.OpenAPI
+	}
+	if overlay.Info.Title != "" {
+		o.Info.Title = overlay.Info.Title
+	}
+	if overlay.Info.Description != "" {
+		o.Info.Description = overlay.Info.Description
+	}
+	if overlay.Info.Version != "" {
+		o.Info.Version = overlay.Info.Version
+	}
+	o.Info.Extra = mergeExtra(o.Info.Extra, overlay.Info.Extra)
+	o.Extra = mergeExtra(o.Extra, overlay.Extra)
+	if overlay.ExternalDocs.URL != "" {
+		o.ExternalDocs = overlay.ExternalDocs
+	}
+	if len(overlay.Servers) > 0 {
+		o.Servers = overlay.Servers
+	}
+	if len(overlay.Security) > 0 {
+		o.Security = overlay.Security
+	}
+
+	for _, tag := range overlay.Tags {
+		var found = false
+		for i := range o.Tags {
+			if o.Tags[i].Name == tag.Name {
+				o.Tags[i], found = tag, true
+			}
+		}
+		if !found {
+			o.Tags = append(o.Tags, tag)
+		}
+	}
+
+	for _, item := range overlay.Paths.Items {
+		var target = o.Paths.Find(item.Path)
+		if target == nil {
+			o.Paths.AddPath(item)
+			continue
+		}
+		target.Extra = mergeExtra(target.Extra, item.Extra)
+		for _, op := range item.Operations {
+			if existing := target.Operation(op.Method); existing != nil {
+				mergeOperation(existing, op)
+			} else {
+				target.Operations = append(target.Operations, op)
+			}
+		}
+	}
+
+	for _, scheme := range overlay.Components.SecuritySchemes {
+		var found = false
+		for i := range o.Components.SecuritySchemes {
+			if o.Components.SecuritySchemes[i].Name == scheme.Name {
+				o.Components.SecuritySchemes[i], found = scheme, true
+			}
+		}
+		if !found {
+			o.Components.SecuritySchemes = append(o.Components.SecuritySchemes, scheme)
+		}
+	}
+	for _, item := range overlay.Components.Schemas {
+		o.Components.AddSchema(item.Name, item.Schema)
+	}
+	o.Components.Extra = mergeExtra(o.Components.Extra, overlay.Components.Extra)
+}
+
+// mergeExtra overrides the kept fields of dst with those of src and adds the others.
+func mergeExtra(dst, src *yaml.Node) *yaml.Node {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		return src
+	}
+	var merged = yaml.Node{Kind: yaml.MappingNode, Content: append([]*yaml.Node{}, dst.Content...)}
+	eachPair(src, func(key string, item *yaml.Node) {
+		for i := 0; i+1 < len(merged.Content); i += 2 {
+			if merged.Content[i].Value == key {
+				merged.Content[i+1] = item
+				return
+			}
+		}
+		appendPair(&merged, key, item)
+	})
+	return &merged
+}
+
+// Find returns the path item of a path.
+func (p *Paths) Find(path string) *PathItem {
+	for _, item := range p.Items {
+		if item.Path == path {
+			return item
+		}
+	}
+	return nil
+}
+
+// Operation returns the operation of a method.
+func (p *PathItem) Operation(method string) *APIEndpoint {
+	for i := range p.Operations {
+		if p.Operations[i].Method == method {
+			return &p.Operations[i]
+		}
+	}
+	return nil
+}
+
+func mergeOperation(dst *APIEndpoint, src APIEndpoint) {
+	if src.OperationID != "" {
+		dst.OperationID = src.OperationID
+	}
+	if src.Summary != "" {
+		dst.Summary = src.Summary
+	}
+	if src.Description != "" {
+		dst.Description = src.Description
+	}
+	if len(src.Tags) > 0 {
+		dst.Tags = src.Tags
+	}
+	if len(src.Security) > 0 {
+		dst.Security = src.Security
+	}
+	if src.Deprecated {
+		dst.Deprecated = true
+	}
+	dst.Extra = mergeExtra(dst.Extra, src.Extra)
+
+	for _, param := range src.Parameters {
+		var found = false
+		for i := range dst.Parameters {
+			if dst.Parameters[i].Name == param.Name && dst.Parameters[i].In == param.In && dst.Parameters[i].Ref == param.Ref {
+				dst.Parameters[i], found = param, true
+			}
+		}
+		if !found {
+			dst.Parameters = append(dst.Parameters, param)
+		}
+	}
+
+	if src.RequestBody != nil {
+		if dst.RequestBody == nil || src.RequestBody.Ref != "" {
+			dst.RequestBody = src.RequestBody
+		} else {
+			if src.RequestBody.Description != "" {
+				dst.RequestBody.Description = src.RequestBody.Description
+			}
+			if src.RequestBody.Required {
+				dst.RequestBody.Required = true
+			}
+			dst.RequestBody.Extra = mergeExtra(dst.RequestBody.Extra, src.RequestBody.Extra)
+			for _, content := range src.RequestBody.Content {
+				dst.RequestBody.Content = mergeRequestContent(dst.RequestBody.Content, content)
+			}
+		}
+	}
+
+	for _, response := range src.Responses {
+		var target *Response
+		for i := range dst.Responses {
+			if dst.Responses[i].StatusCode == response.StatusCode {
+				target = &dst.Responses[i]
+			}
+		}
+		if target == nil {
+			dst.Responses = append(dst.Responses, response)
+			continue
+		}
+		if response.Ref != "" {
+			*target = response
+			continue
+		}
+		if response.Description != "" {
+			target.Description = response.Description
+		}
+		target.Extra = mergeExtra(target.Extra, response.Extra)
+		for _, content := range response.Content {
+			target.Content = mergeResponseContent(target.Content, content)
+		}
+		for _, header := range response.Headers {
+			var found = false
+			for i := range target.Headers {
+				if target.Headers[i].Name == header.Name {
+					target.Headers[i], found = header, true
+				}
+			}
+			if !found {
+				target.Headers = append(target.Headers, header)
+			}
+		}
+	}
+}
+
+// mergeRequestContent overrides the schema, example and other fields of a media type, or adds it.
+func mergeRequestContent(list []RequestContentType, content RequestContentType) []RequestContentType {
+	for i := range list {
+		if list[i].ContentType != content.ContentType {
+			continue
+		}
+		if content.Schema != nil {
+			list[i].Schema = content.Schema
+		}
+		if content.Example != nil {
+			list[i].Example = content.Example
+		}
+		list[i].Extra = mergeExtra(list[i].Extra, content.Extra)
+		return list
+	}
+	return append(list, content)
+}
+
+// mergeResponseContent overrides the schema, example and other fields of a media type, or adds it.
+func mergeResponseContent(list []ResponseContentType, content ResponseContentType) []ResponseContentType {
+	for i := range list {
+		if list[i].ContentType != content.ContentType {
+			continue
+		}
+		if content.Schema != nil {
+			list[i].Schema = content.Schema
+		}
+		if content.Example != nil {
+			list[i].Example = content.Example
+		}
+		list[i].Extra = mergeExtra(list[i].Extra, content.Extra)
+		return list
+	}
+	return append(list, content)
+}
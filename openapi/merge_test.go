@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generated mimics a document built by ParseRestify.
+func generated() *OpenAPI {
+	var minimum = 1.0
+	var obj = OpenAPI{OpenAPI: "3.0.3"}
+	obj.Info.Title, obj.Info.Version = "Generated", "1.0.0"
+	obj.Tags = []Tag{{Name: "app.User", Description: "generated"}}
+	obj.Components.AddSchema("app.User", Schema{
+		Type: "object",
+		Properties: []SchemaProperty{
+			{Name: "id", Schema: Schema{Type: "integer", Format: "int64", Minimum: &minimum, ExclusiveMinimum: true}},
+			{Name: "email", Schema: Schema{Type: "string", Nullable: true}},
+		},
+	})
+	obj.Paths.AddPath(&PathItem{Path: "/users/{id}", Operations: []APIEndpoint{{
+		Method:      "GET",
+		Summary:     "get user",
+		Description: "generated description",
+		Tags:        []string{"app.User"},
+		Parameters: []Parameter{
+			{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}},
+			{Name: "fields", In: "query", Schema: &Schema{Type: "string"}},
+		},
+		Responses: []Response{
+			{StatusCode: "200", Description: "OK", Content: []ResponseContentType{{ContentType: "application/json", Schema: SchemaRef("app.User")}}},
+			{StatusCode: "404", Description: "Object does not exist"},
+		},
+	}}})
+	return &obj
+}
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	for _, version := range []string{"3.0.3", "3.1.0"} {
+		var obj = generated()
+		obj.OpenAPI = version
+		b, err := obj.GenerateYaml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var loaded OpenAPI
+		if err = yaml.Unmarshal(b, &loaded); err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		again, err := loaded.GenerateYaml()
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if string(again) != string(b) {
+			t.Errorf("%s: round trip changed the document\n%s\n---\n%s", version, b, again)
+		}
+
+		var user, _ = loaded.Components.Schema("app.User")
+		if len(user.Properties) != 2 || !user.Properties[1].Nullable || user.Properties[0].Minimum == nil || !user.Properties[0].ExclusiveMinimum {
+			t.Errorf("%s: schema keywords were lost: %+v", version, user)
+		}
+		var op = loaded.Paths.Find("/users/{id}").Operation("GET")
+		if op == nil || len(op.Parameters) != 2 || len(op.Responses) != 2 || op.Responses[0].Content[0].Schema.Ref == "" {
+			t.Errorf("%s: operation was lost: %+v", version, op)
+		}
+	}
+}
+
+// handWritten uses keywords and components the generator never writes.
+const handWritten = `
+openapi: 3.0.3
+info:
+  title: Shop
+  description: hand-written
+  version: 2.0.0
+  contact: {name: Shop team, email: shop@example.com}
+  license: {name: MIT}
+  x-logo: {url: https://example.com/logo.png}
+externalDocs: {url: https://example.com/docs}
+servers:
+  - url: https://{region}.example.com
+    variables:
+      region: {default: eu}
+tags:
+  - name: orders
+    description: order endpoints
+    externalDocs: {url: https://example.com/orders}
+paths:
+  /orders/{id}:
+    summary: a single order
+    description: orders are immutable once paid
+    get:
+      operationId: getOrder
+      tags: [orders]
+      externalDocs: {url: https://example.com/get-order}
+      parameters:
+        - $ref: '#/components/parameters/OrderID'
+        - name: expand
+          in: query
+          deprecated: true
+          allowEmptyValue: true
+          example: items
+          schema: {type: string}
+      responses:
+        "200":
+          description: the order
+          headers:
+            X-Rate-Limit:
+              $ref: '#/components/headers/RateLimit'
+          links:
+            customer: {operationId: getCustomer}
+          content:
+            application/json:
+              schema:
+                oneOf:
+                  - $ref: '#/components/schemas/Order'
+                  - {type: string, nullable: true}
+              examples:
+                paid: {value: {id: 1, status: paid}}
+        "404":
+          $ref: '#/components/responses/NotFound'
+      callbacks: {}
+    put:
+      parameters:
+        - $ref: '#/components/parameters/OrderID'
+      requestBody:
+        $ref: '#/components/requestBodies/Order'
+      responses:
+        default: {description: error}
+components:
+  schemas:
+    Order:
+      type: object
+      title: Order
+      additionalProperties: false
+      minProperties: 1
+      properties:
+        id: {type: integer, readOnly: true, minimum: 1}
+        status: {type: string, enum: [new, paid], default: new}
+        note: {type: string, writeOnly: true}
+        lines:
+          type: array
+          minItems: 1
+          uniqueItems: true
+          items: {anyOf: [{type: string}, {type: integer, nullable: true}]}
+        code: {not: {type: integer}}
+        customer:
+          $ref: '#/components/schemas/Customer'
+          description: ignored sibling in 3.0
+    Customer: {type: object, discriminator: {propertyName: kind}, x-internal: true}
+  responses:
+    NotFound: {description: not found}
+  parameters:
+    OrderID: {name: id, in: path, required: true, schema: {type: integer}}
+  requestBodies:
+    Order:
+      required: true
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Order'}
+          encoding: {note: {contentType: text/plain}}
+  examples:
+    Paid: {value: {status: paid}}
+  headers:
+    RateLimit: {description: calls left, schema: {type: integer}}
+  securitySchemes:
+    Key: {type: apiKey, name: X-Key, in: header, x-vendor: gateway}
+security:
+  - Key: []
+x-generated-by: hand
+`
+
+func TestUnmarshalHandWritten(t *testing.T) {
+	var obj OpenAPI
+	if err := yaml.Unmarshal([]byte(handWritten), &obj); err != nil {
+		t.Fatal(err)
+	}
+	b, err := obj.GenerateYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got interface{}
+	if err = yaml.Unmarshal([]byte(handWritten), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err = yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("round trip changed the document\n%s", b)
+	}
+
+	// writing the document as 3.1 must not rewrite the kept fields of the object
+	obj.OpenAPI = "3.1.0"
+	if _, err = obj.GenerateYaml(); err != nil {
+		t.Fatal(err)
+	}
+	var order, _ = obj.Components.Schema("Order")
+	if again, _ := marshalSchema(order); !strings.Contains(yamlString(t, again), "minProperties: 1") {
+		t.Errorf("schema keywords were lost: %s", yamlString(t, again))
+	}
+	var anyOf = value(order.Properties[3].Items.Extra, "anyOf")
+	if anyOf == nil || value(anyOf.Content[1], "nullable") == nil {
+		t.Errorf("the kept anyOf was rewritten in place: %+v", anyOf)
+	}
+}
+
+func TestMergeHandWritten(t *testing.T) {
+	var overlay OpenAPI
+	if err := yaml.Unmarshal([]byte(handWritten), &overlay); err != nil {
+		t.Fatal(err)
+	}
+	var obj = generated()
+	obj.Merge(&overlay)
+	b, err := obj.GenerateYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"x-generated-by: hand", "contact:", "NotFound:", "requestBodies:", "summary: a single order", "links:", "x-vendor: gateway"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("merged document misses %q:\n%s", want, b)
+		}
+	}
+}
+
+func yamlString(t *testing.T, node *yaml.Node) string {
+	t.Helper()
+	b, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestUnmarshalSharedParameters(t *testing.T) {
+	var obj OpenAPI
+	var err = yaml.Unmarshal([]byte(`
+openapi: 3.0.3
+info: {title: API, version: '1'}
+paths:
+  /items/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      responses: {"200": {description: OK}}
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, description: own, schema: {type: string}}
+      responses: {"200": {description: OK}}
+`), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var item = obj.Paths.Find("/items/{id}")
+	if get := item.Operation("GET"); get == nil || len(get.Parameters) != 1 {
+		t.Fatalf("shared parameters are not added to operations: %+v", get)
+	}
+	if del := item.Operation("DELETE"); len(del.Parameters) != 1 || del.Parameters[0].Description != "own" {
+		t.Errorf("operation parameters must win over shared ones: %+v", del.Parameters)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var obj = generated()
+	var overlay OpenAPI
+	var err = yaml.Unmarshal([]byte(`
+openapi: 3.0.3
+info:
+  title: Custom
+  version: 2.0.0
+security:
+  - ApiKey: []
+tags:
+  - name: app.User
+    description: hand-written
+paths:
+  /users/{id}:
+    get:
+      description: hand-written description
+      parameters:
+        - {name: fields, in: query, description: pick fields, schema: {type: string}}
+        - {name: X-Tenant, in: header, schema: {type: string}}
+      responses:
+        "200":
+          description: the user
+          content:
+            application/json:
+              example: {id: 1}
+        "410":
+          description: Gone
+  /health:
+    get:
+      responses:
+        "200": {description: ok}
+components:
+  securitySchemes:
+    ApiKey: {type: apiKey, name: X-Key, in: header}
+  schemas:
+    Health:
+      type: object
+`), &overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj.Merge(&overlay)
+
+	if obj.Info.Title != "Custom" || obj.Info.Version != "2.0.0" || len(obj.Security) != 1 {
+		t.Errorf("info and security are not overridden: %+v %+v", obj.Info, obj.Security)
+	}
+	if len(obj.Tags) != 1 || obj.Tags[0].Description != "hand-written" {
+		t.Errorf("tags are not merged by name: %+v", obj.Tags)
+	}
+	var op = obj.Paths.Find("/users/{id}").Operation("GET")
+	if op.Description != "hand-written description" || op.Summary != "get user" {
+		t.Errorf("descriptions are not merged: %q %q", op.Summary, op.Description)
+	}
+	if len(op.Parameters) != 3 || op.Parameters[1].Description != "pick fields" || op.Parameters[2].Name != "X-Tenant" {
+		t.Errorf("parameters are not merged by name and location: %+v", op.Parameters)
+	}
+	if len(op.Responses) != 3 || op.Responses[0].Description != "the user" {
+		t.Fatalf("responses are not merged by status: %+v", op.Responses)
+	}
+	if content := op.Responses[0].Content[0]; content.Schema == nil || content.Schema.Ref != "#/components/schemas/app.User" || content.Example == nil {
+		t.Errorf("an example must keep the generated schema: %+v", content)
+	}
+	if obj.Paths.Find("/health") == nil {
+		t.Error("hand-written paths are not added")
+	}
+	if _, ok := obj.Components.Schema("Health"); !ok {
+		t.Error("hand-written schemas are not added")
+	}
+	if _, ok := obj.Components.Schema("app.User"); !ok {
+		t.Error("generated schemas must be kept")
+	}
+
+	b, err := obj.GenerateYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "ApiKey:") || !strings.Contains(string(b), "example:") {
+		t.Errorf("merged document misses the overlay:\n%s", b)
+	}
+}
@@ -1,105 +1,76 @@
 package openapi
 
 import (
+	"errors"
 	"fmt"
-	"github.com/getevo/evo/v2/lib/log"
+	"github.com/getevo/docify/serializer"
 	"github.com/getevo/restify"
+	"gopkg.in/yaml.v3"
 	"gorm.io/gorm/schema"
 	"reflect"
 	"sort"
 	"strings"
 )
 
-var stdResponse = []Response{
-	{
-		StatusCode:  "400",
-		Description: "Data validation error",
-	},
-	{
-		StatusCode:  "403",
-		Description: "Authentication required",
-	},
-	{
-		StatusCode:  "5XX",
-		Description: "Server error",
-	},
-}
-
-func (o *OpenAPI) ParseRestify() {
-
-	var resources []*restify.Resource
-
-	for idx, _ := range restify.Resources {
-		resources = append(resources, restify.Resources[idx])
-	}
-	sort.Slice(resources, func(i, j int) bool {
-		return resources[i].Table < resources[j].Table
-	})
-
-	for _, resource := range resources {
+// ParseRestify adds the tags and paths of the documented entities. Every entity is described
+// once under components.schemas, with its Create, Update and Batch bodies and its response
+// envelopes, and referenced from the operations.
+func (o *OpenAPI) ParseRestify(doc *serializer.Doc) error {
+	var errs []error
+	for i := range doc.Entities {
+		var entity = &doc.Entities[i]
+		var resource = entity.Resource
 		var chunks = strings.Split(resource.Name, ".")
 		var description = "App:" + chunks[0] + " Entity:" + chunks[1]
 		o.Tags = append(o.Tags, Tag{
 			Name:        resource.Name,
 			Description: description,
 		})
-		var body, err = GetRequestBody(resource)
-		if err != nil {
-			log.Fatal(err)
-		}
+		var fields = entityFields(doc, resource)
+		o.Components.AddSchema(SchemaName(entity), responseObject(entity, fields))
+
 		var paths = map[string]*PathItem{}
 		for _, action := range resource.Actions {
+			var op, found = entityOperation(doc, action)
+			if !found {
+				op = serializer.NewOperation(&serializer.Entity{}, action)
+			}
+
+			var path = op.OpenAPIPath()
 			var pathItem *PathItem
 			var ok bool
-			if pathItem, ok = paths[action.AbsoluteURI]; !ok {
+			if pathItem, ok = paths[path]; !ok {
 				pathItem = &PathItem{
-					Path: action.AbsoluteURI,
+					Path: path,
 				}
-				paths[action.AbsoluteURI] = pathItem
+				paths[path] = pathItem
 			}
 
-			var responseProperties []SchemaProperty
-
-			for _, field := range resource.Schema.Fields {
-				var jsonField = strings.Split(field.Tag.Get("json"), ",")[0]
-				if jsonField == "-" {
-					continue
-				}
-				if jsonField == "" {
-					jsonField = field.Name
-				}
-				responseProperties = append(responseProperties, SchemaProperty{
-					Name:        jsonField,
-					Description: field.Name,
-					Type:        getType(field),
-				})
-			}
-
-			var responses = []Response{
-				{
-					StatusCode:  "200",
-					Description: "OK",
-					Content: []ResponseContentType{
-						{
-							ContentType: "application/json",
-							Schema: &Schema{
-								Type:       "object",
-								Properties: responseProperties,
-							},
-						},
-					},
-				},
-			}
 			var api = APIEndpoint{
-				Method:      string(action.Method),
-				Summary:     action.Description,
-				Description: action.Description,
+				Method:      op.Method,
+				Summary:     op.Description,
+				Description: op.Description,
 				Tags:        []string{resource.Name},
-				Responses:   append(responses, stdResponse...),
+				Parameters:  operationParameters(op),
+			}
+			for _, response := range op.Responses {
+				var item = Response{
+					StatusCode:  response.Status,
+					Description: response.Description,
+				}
+				if schema := o.responseSchema(entity, response); schema != nil {
+					item.Content = []ResponseContentType{{ContentType: serializer.ContentTypeJSON, Schema: schema}}
+				}
+				api.Responses = append(api.Responses, item)
 			}
 
-			if action.AcceptData {
-				api.RequestBody = body
+			if op.Body != nil {
+				var name, err = o.bodySchema(entity, fields, op.Body)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", resource.Name, err))
+				} else {
+					api.RequestBody = operationBody(entity, name, op.Body)
+				}
 			}
 			pathItem.Operations = append(pathItem.Operations, api)
 
@@ -110,52 +81,401 @@ func (o *OpenAPI) ParseRestify() {
 		}
 
 	}
+	return errors.Join(errs...)
 }
 
-// getOpenAPIType is a helper that returns a naive type mapping for common Go/GORM field types.
-// You can customize it to better map your decimal, time, etc.
-func getType(field *schema.Field) string {
-	goType := field.DataType
-
-	switch goType {
-	case schema.String:
-		return "string"
-	case schema.Int, schema.Uint:
-		return "integer"
-	case schema.Bool:
-		return "boolean"
-	case schema.Float:
-		return "number"
-	case schema.Time:
-		return "string" // date-time could be used, but we'll keep it simple
-	case schema.Bytes:
-		return "string" // or "binary"
-	}
-	return "string"
+// SchemaName returns the name of the component schema describing an entity.
+func SchemaName(entity *serializer.Entity) string {
+	return entity.Pkg + "." + entity.Name
+}
+
+// bodySchema adds the component schema of a request body variant once and returns its name.
+func (o *OpenAPI) bodySchema(entity *serializer.Entity, fields map[string]serializer.Field, body *serializer.Body) (string, error) {
+	var name = SchemaName(entity) + "Update"
+	var filter = serializer.Field.InUpdate
+	if body.Variant == serializer.BodyCreate {
+		name, filter = SchemaName(entity)+"Create", serializer.Field.InCreate
+	}
+	if _, ok := o.Components.Schema(name); !ok {
+		s, err := requestSchema(entity.Resource, fields, filter)
+		if err != nil {
+			return "", err
+		}
+		if body.Variant == serializer.BodyUpdate {
+			// updates are partial, nothing is required
+			s.Required = nil
+		}
+		o.Components.AddSchema(name, *s)
+	}
+	if !body.Batch {
+		return name, nil
+	}
+	var batch = SchemaName(entity) + "Batch"
+	o.Components.AddSchema(batch, Schema{Type: "array", Items: SchemaRef(name)})
+	return batch, nil
+}
+
+// responseObject describes an entity as it is returned, associations reference their entity schema.
+func responseObject(entity *serializer.Entity, fields map[string]serializer.Field) Schema {
+	var object = Schema{Type: "object", Description: entity.Description}
+	var resource = entity.Resource
+	for _, field := range resource.Schema.Fields {
+		var item = lookupField(fields, field)
+		if field.DBName == "" {
+			if item.Visibility.Hidden {
+				continue
+			}
+			if association, ok := entityAssociation(entity, field); ok {
+				object.Properties = append(object.Properties, associationProperty(item.JsonTag, association))
+			}
+			continue
+		}
+		if !item.InResponse() {
+			continue
+		}
+		var description = field.Name
+		if v := item.Description; v != "" {
+			description = v
+		}
+		object.Properties = append(object.Properties, fieldProperty(item.JsonTag, description, field, item))
+	}
+	return object
+}
+
+// entityAssociation returns the resolved association of a relation field.
+func entityAssociation(entity *serializer.Entity, field *schema.Field) (serializer.Association, bool) {
+	for _, association := range entity.Association {
+		if association.Name == field.Name {
+			return association, true
+		}
+	}
+	return serializer.NewAssociation(field, entity.Resource.Schema)
+}
+
+// entityFields returns the serialized fields of the entity documenting resource, keyed by Go field name.
+func entityFields(doc *serializer.Doc, resource *restify.Resource) map[string]serializer.Field {
+	var fields = map[string]serializer.Field{}
+	if doc == nil {
+		return fields
+	}
+	for _, entity := range doc.Entities {
+		if entity.ID != resource.Name {
+			continue
+		}
+		for _, field := range entity.Fields {
+			fields[field.Name] = field
+		}
+	}
+	return fields
+}
+
+// entityOperation returns the documented operation of a restify endpoint.
+func entityOperation(doc *serializer.Doc, endpoint *restify.Endpoint) (serializer.Operation, bool) {
+	if doc == nil {
+		return serializer.Operation{}, false
+	}
+	for _, entity := range doc.Entities {
+		for _, op := range entity.Operations {
+			if op.Endpoint == endpoint {
+				return op, true
+			}
+		}
+	}
+	return serializer.Operation{}, false
+}
+
+// operationParameters describes the path parameters of the primary keys and the query
+// parameters of an operation. Filters are deep objects keyed by operator, as in `price[gte]=10`.
+func operationParameters(op serializer.Operation) []Parameter {
+	var params []Parameter
+	for _, param := range append(op.PathParams, op.QueryParams...) {
+		var item = Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Required:    param.Required,
+			Description: param.Description,
+			Schema:      parameterSchema(param),
+		}
+		if param.Style == "deepObject" {
+			item.Style, item.Explode = param.Style, true
+			item.Schema = filterSchema(param)
+		}
+		params = append(params, item)
+	}
+	return params
+}
+
+// parameterSchema returns the schema of a scalar parameter with its example.
+func parameterSchema(param serializer.Parameter) *Schema {
+	var s = Schema{Type: param.Type, Format: param.Format, Enum: param.Enum}
+	if param.Field != nil && len(param.Field.Enum) > 0 {
+		s.Enum = param.Field.Enum
+	}
+	switch {
+	case param.Example == "":
+	case param.Type == "string":
+		s.Example = stringNode(param.Example)
+	case param.Type == "integer" || param.Type == "number":
+		s.Example = &yaml.Node{Kind: yaml.ScalarNode, Value: param.Example}
+	}
+	return &s
+}
+
+// filterSchema describes the operators of a filter; list operators take comma separated
+// values and null checks take any value.
+func filterSchema(param serializer.Parameter) *Schema {
+	var value = Schema{Type: "string"}
+	if param.Field != nil && param.Field.JsonType != "" && param.Field.JsonType != "object" && param.Field.JsonType != "array" {
+		value = Schema{Type: param.Field.JsonType, Format: param.Field.Format, Enum: param.Field.Enum}
+	}
+	var s = Schema{Type: "object"}
+	for _, operator := range serializer.FilterOperators {
+		var property = SchemaProperty{Name: operator, Schema: value}
+		switch operator {
+		case "in", "notin":
+			property.Schema = Schema{Type: "string", Description: "comma separated values"}
+		case "between":
+			property.Schema = Schema{Type: "string", Description: "comma separated lower and upper bounds"}
+		case "contains", "search":
+			property.Schema = Schema{Type: "string"}
+		case "isnull", "notnull":
+			property.Schema = Schema{Type: "string", Description: "any value"}
+		}
+		s.Properties = append(s.Properties, property)
+	}
+	return &s
+}
+
+// operationBody references the body schema for every content type the operation accepts.
+func operationBody(entity *serializer.Entity, name string, accepts *serializer.Body) *RequestBody {
+	var result = RequestBody{Description: fmt.Sprintf("%s body of %s", accepts.Variant, entity.ID)}
+	if accepts.Batch {
+		result.Description = fmt.Sprintf("list of %s bodies of %s", accepts.Variant, entity.ID)
+	}
+	for _, contentType := range accepts.ContentTypes {
+		result.Content = append(result.Content, RequestContentType{ContentType: contentType, Schema: SchemaRef(name)})
+	}
+	return &result
+}
+
+// lookupField returns the serialized field of a gorm field, or one carrying only its visibility
+// when the field is not documented.
+func lookupField(fields map[string]serializer.Field, field *schema.Field) serializer.Field {
+	if item, ok := fields[field.Name]; ok {
+		return item
+	}
+	var visibility = serializer.NewVisibility(field)
+	return serializer.Field{
+		Name:          field.Name,
+		JsonTag:       visibility.JsonName,
+		DBName:        field.DBName,
+		PrimaryKey:    field.PrimaryKey,
+		AutoIncrement: field.AutoIncrement,
+		Visibility:    visibility,
+	}
+}
+
+// fieldProperty builds a schema property for a field, expanding nested structs,
+// slices, maps and JSON columns into their real shape.
+func fieldProperty(name, description string, field *schema.Field, doc serializer.Field) SchemaProperty {
+	var mapping = serializer.MapType(field.FieldType)
+	var prop = SchemaProperty{
+		Name: name,
+		Schema: Schema{
+			Type:        mapping.JsonType,
+			Format:      mapping.Format,
+			Description: description,
+			Enum:        doc.Enum,
+			Nullable:    nullable(field.FieldType),
+		},
+	}
+	if doc.JsonType != "" {
+		prop.Type, prop.Format = doc.JsonType, doc.Format
+	}
+	if nested := serializer.DescribeType(field.FieldType); nested.IsComplex() {
+		var s = schemaFromField(nested)
+		prop.Type = s.Type
+		prop.Properties = s.Properties
+		prop.Items = s.Items
+		prop.AdditionalProperties = s.AdditionalProperties
+	}
+	applyRules(&prop.Schema, doc.Rules)
+	return prop
+}
+
+// nullable reports whether values of a type may be null in JSON: pointers, sql.Null* and
+// similar wrappers, and soft delete times.
+func nullable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return true
+	}
+	return strings.HasPrefix(t.Name(), "Null") || t.String() == "gorm.DeletedAt"
+}
+
+// applyRules maps parsed validation rules to schema keywords.
+func applyRules(s *Schema, rules serializer.ValidationRules) {
+	min, max := rules.LengthRange()
+	if min > -1 {
+		s.MinLength = &min
+	}
+	if max > -1 {
+		s.MaxLength = &max
+	}
+	for _, rule := range rules {
+		var n = rule.Number
+		switch rule.Kind {
+		case serializer.RuleMinimum:
+			s.Minimum = &n
+			s.ExclusiveMinimum = rule.Exclusive
+		case serializer.RuleMaximum:
+			s.Maximum = &n
+			s.ExclusiveMaximum = rule.Exclusive
+		case serializer.RuleEqual:
+			s.Minimum, s.Maximum = &n, &n
+		case serializer.RuleFormat:
+			s.Format = rule.Value
+		case serializer.RuleOneOf:
+			if len(s.Enum) == 0 {
+				s.Enum = rule.Values
+			}
+		}
+	}
+	if pattern := rules.Pattern(); pattern != "" {
+		s.Pattern = pattern
+	}
+}
+
+// associationProperty describes an association which is loaded on demand using the associations query parameter.
+func associationProperty(name string, association serializer.Association) SchemaProperty {
+	var description = fmt.Sprintf("%s association to %s, loaded with ?associations=%s", association.Relation(), association.EntityName, association.Name)
+	if association.JoinTable != "" {
+		description += " through " + association.JoinTable
+	}
+	var item = &Schema{Type: "object"}
+	if association.Entity != nil {
+		item = SchemaRef(SchemaName(association.Entity))
+	}
+	if association.Array {
+		return SchemaProperty{
+			Name: name,
+			Schema: Schema{
+				Type:        "array",
+				Description: description,
+				Items:       item,
+			},
+		}
+	}
+	if item.Ref != "" {
+		return SchemaProperty{
+			Name: name,
+			Schema: Schema{
+				AllOf:       []*Schema{item},
+				Description: description,
+				Nullable:    true,
+			},
+		}
+	}
+	return SchemaProperty{
+		Name: name,
+		Schema: Schema{
+			Type:        "object",
+			Description: description,
+			Nullable:    true,
+		},
+	}
+}
+
+// schemaFromField converts a serializer field description into a schema.
+func schemaFromField(f serializer.Field) *Schema {
+	var s = Schema{
+		Type:        f.JsonType,
+		Format:      f.Format,
+		Description: f.Description,
+		Enum:        f.Enum,
+	}
+	for _, property := range f.Properties {
+		s.Properties = append(s.Properties, SchemaProperty{
+			Name:   property.JsonTag,
+			Schema: *schemaFromField(property),
+		})
+	}
+	if f.Items != nil {
+		s.Items = schemaFromField(*f.Items)
+	}
+	if f.Values != nil {
+		s.AdditionalProperties = schemaFromField(*f.Values)
+	}
+	return &s
 }
 
 // GetRequestBody builds an OpenAPI RequestBody for the given model,
 // including only direct columns (no foreign-key relationships).
-func GetRequestBody(resource *restify.Resource) (*RequestBody, error) {
+func GetRequestBody(resource *restify.Resource, fields map[string]serializer.Field) (*RequestBody, error) {
+	s, err := requestSchema(resource, fields, serializer.Field.InRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive the model name from its type for the description
+	modelName := resource.Type.String()
+	if resource.Type.Kind() == reflect.Ptr {
+		modelName = resource.Type.Elem().String()
+	}
+	desc := fmt.Sprintf("Request body for %s", modelName)
+
+	// Build the final RequestBody
+	requestBody := RequestBody{
+		Description: desc,
+		Content: []RequestContentType{
+			{
+				ContentType: serializer.ContentTypeJSON,
+				Schema:      s,
+			},
+			{
+				ContentType: serializer.ContentTypeForm,
+				Schema:      s,
+			},
+			{
+				ContentType: serializer.ContentTypeMultipart,
+				Schema:      s,
+			},
+		},
+	}
+
+	return &requestBody, nil
+}
+
+// requestSchema describes the direct columns of a model accepted by a request body.
+func requestSchema(resource *restify.Resource, fields map[string]serializer.Field, accepts func(serializer.Field) bool) (*Schema, error) {
 
 	// Prepare schema properties
 	var properties []SchemaProperty
+	var required []string
 
 	for _, field := range resource.Schema.Fields {
-		if field.AutoIncrement {
-			continue // ignore auto-increment fields
-		}
 		if field.DBName == "" {
 			continue // ignore fields without a DBName
 		}
+		var item = lookupField(fields, field)
+		if !accepts(item) {
+			continue // ignore auto-increment, read-only and hidden fields
+		}
 		// Build a property
-		propType := getType(field)
 		isPtr := resource.Ref.FieldByName(field.Name).Kind() == reflect.Ptr
 		var description = "<ul>"
 		var optional = true
-		if field.Comment != "" {
+		if v := item.Description; v != "" {
+			description += "<li>" + strings.ReplaceAll(v, "\n", "<br>") + "</li>"
+		} else if field.Comment != "" {
 			description += "<li>" + strings.TrimSpace(field.Comment) + "</li>"
 		}
+		if values := item.EnumValues; len(values) > 0 {
+			description += "<li>Enum:<ul>"
+			for _, item := range values {
+				description += "<li><code>" + item.Value + "</code> " + item.Description + "</li>"
+			}
+			description += "</ul></li>"
+		}
 		if v, ok := field.TagSettings["FK"]; ok {
 			var selfRef = ""
 			if v == resource.Table {
@@ -166,9 +486,13 @@ func GetRequestBody(resource *restify.Resource) (*RequestBody, error) {
 				optional = false
 			}
 		}
-		if v, ok := field.Tag.Lookup("validation"); ok {
-			description += "<li>Validation: " + v + "</li>"
-			if strings.Contains(v, "required") {
+		var rules = item.Rules
+		if rules == nil {
+			rules = serializer.ParseValidation(field.Tag.Get("validation"))
+		}
+		if len(rules) > 0 {
+			description += "<li>Validation: " + rules.Text() + "</li>"
+			if rules.Required() {
 				optional = false
 			}
 		}
@@ -189,6 +513,11 @@ func GetRequestBody(resource *restify.Resource) (*RequestBody, error) {
 		if v, ok := field.TagSettings["INDEX"]; ok {
 			description += "<li>Index: " + v + "</li>"
 		}
+		if !item.InUpdate() {
+			description += "<li>Cannot be updated</li>"
+		} else if !item.InCreate() {
+			description += "<li>Cannot be created</li>"
+		}
 		if optional {
 			description += "<li><b>Optional</b></li>"
 		} else {
@@ -196,44 +525,20 @@ func GetRequestBody(resource *restify.Resource) (*RequestBody, error) {
 		}
 
 		description += "</ul>"
-		prop := SchemaProperty{
-			Name: field.DBName, // e.g. "payment_transaction_id"
-			Type: propType,     // e.g. "integer"
-			// optionally set a description if needed
-			Description: description,
-		}
+		prop := fieldProperty(item.JsonTag, description, field, item)
 		properties = append(properties, prop)
+		if !optional {
+			required = append(required, item.JsonTag)
+		}
 	}
 
 	// Build the schema
 	s := Schema{
 		Type:       "object",
 		Properties: properties,
+		Required:   required,
 	}
-
-	// Derive the model name from its type for the description
-	modelName := resource.Type.String()
-	if resource.Type.Kind() == reflect.Ptr {
-		modelName = resource.Type.Elem().String()
-	}
-	desc := fmt.Sprintf("Request body for %s", modelName)
-
-	// Build the final RequestBody
-	requestBody := RequestBody{
-		Description: desc,
-		Content: []RequestContentType{
-			{
-				ContentType: "application/x-www-form-urlencoded",
-				Schema:      &s,
-			},
-			{
-				ContentType: "application/json",
-				Schema:      &s,
-			},
-		},
-	}
-
-	return &requestBody, nil
+	return &s, nil
 }
 
 func orderedKeys(paths map[string]*PathItem) []*PathItem {
@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/getevo/docify/serializer"
+	"github.com/getevo/restify"
+	"gorm.io/gorm/schema"
+)
+
+// Item has columns named like the restify query parameters.
+type Item struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Order int    `json:"order"`
+	Size  string `json:"size"`
+	Name  string `json:"name" validation:"required"`
+}
+
+// itemDoc documents Item with the usual restify endpoints.
+func itemDoc(t *testing.T) *serializer.Doc {
+	t.Helper()
+	s, err := schema.Parse(&Item{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resource = &restify.Resource{Name: "app.Item", Table: "items", Schema: s, Type: reflect.TypeOf(Item{}), Ref: reflect.ValueOf(&Item{}).Elem()}
+	resource.Actions = []*restify.Endpoint{
+		{Name: "ALL", Method: "GET", Filterable: true, AbsoluteURI: "/items/all"},
+		{Name: "PAGINATE", Method: "GET", Filterable: true, Pagination: true, AbsoluteURI: "/items/paginate"},
+		{Name: "GET", Method: "GET", PKUrl: true, Filterable: true, AbsoluteURI: "/items/get/:id"},
+		{Name: "CREATE", Method: "PUT", AcceptData: true, AbsoluteURI: "/items/create"},
+		{Name: "BATCH.CREATE", Method: "PUT", AcceptData: true, Batch: true, AbsoluteURI: "/items/batch"},
+		{Name: "BATCH.UPDATE", Method: "PATCH", Filterable: true, Batch: true, AbsoluteURI: "/items/batch"},
+		{Name: "UPDATE", Method: "PATCH", PKUrl: true, AcceptData: true, AbsoluteURI: "/items/update/:id"},
+		{Name: "DELETE", Method: "DELETE", PKUrl: true, AbsoluteURI: "/items/delete/:id"},
+	}
+	var entity = serializer.Entity{ID: resource.Name, Name: "Item", Pkg: "app", Resource: resource, Endpoints: resource.Actions}
+	for _, field := range s.Fields {
+		var visibility = serializer.NewVisibility(field)
+		var mapping = serializer.MapType(field.FieldType)
+		entity.Fields = append(entity.Fields, serializer.Field{
+			Name: field.Name, JsonTag: visibility.JsonName, DBName: field.DBName, JsonType: mapping.JsonType, Format: mapping.Format,
+			PrimaryKey: field.PrimaryKey, Visibility: visibility, Rules: serializer.ParseValidation(field.Tag.Get("validation")),
+		})
+	}
+	entity.Operations = serializer.NewOperations(&entity)
+	return &serializer.Doc{Title: "Items", Entities: []serializer.Entity{entity}}
+}
+
+func TestParseRestifyParameters(t *testing.T) {
+	var obj = OpenAPI{OpenAPI: "3.0.3"}
+	obj.Info.Title, obj.Info.Version = "Items", "1.0.0"
+	if err := obj.ParseRestify(itemDoc(t)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.GenerateYaml(); err != nil {
+		t.Fatalf("columns named like restify parameters break the document: %v", err)
+	}
+
+	var get = obj.Paths.Find("/items/get/{id}")
+	if get == nil {
+		t.Fatal("path parameters are not written as {id}")
+	}
+	var params = map[string]Parameter{}
+	for _, param := range get.Operation("GET").Parameters {
+		params[param.In+":"+param.Name] = param
+	}
+	if id := params["path:id"]; !id.Required || id.Schema.Type != "integer" {
+		t.Errorf("primary key path parameter: %+v", id)
+	}
+	if name := params["query:name"]; name.Style != "deepObject" || !name.Explode || name.Schema.Type != "object" {
+		t.Errorf("filters must be exploded deep objects: %+v", name)
+	}
+	if order := params["query:order"]; order.Style != "" || !strings.Contains(order.Description, "order[op]=value") {
+		t.Errorf("order must stay the sort parameter: %+v", order)
+	}
+
+	var paginate = obj.Paths.Find("/items/paginate").Operation("GET")
+	var names []string
+	for _, param := range paginate.Parameters {
+		names = append(names, param.Name)
+	}
+	if !strings.Contains(strings.Join(names, ","), "page,size") {
+		t.Errorf("paginated endpoints declare page and size: %v", names)
+	}
+}
@@ -1,8 +1,8 @@
 package openapi
 
 import (
-	"fmt"
 	"gopkg.in/yaml.v3"
+	"strconv"
 	"strings"
 )
 
@@ -16,10 +16,144 @@ type OpenAPI struct {
 	Paths        Paths          `yaml:"paths"` // Custom type to preserve order
 	Components   Components     `yaml:"components,omitempty"`
 	Security     []SecurityItem `yaml:"security,omitempty"`
+	// Extra holds the fields of a hand-written document that are not modeled, such as
+	// webhooks or x- extensions; the other objects keep theirs the same way.
+	Extra *yaml.Node `yaml:"-"`
 }
 
+// GenerateYaml validates the document against the official OpenAPI schema of its version,
+// checks its structure and returns it as YAML.
 func (o *OpenAPI) GenerateYaml() ([]byte, error) {
-	return yaml.Marshal(o)
+	node, err := o.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	if err = ValidateSchema(node.(*yaml.Node)); err != nil {
+		return nil, err
+	}
+	if err = CheckStructure(node.(*yaml.Node)); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+// MarshalYAML emits the document in the shape of the OpenAPI specification; optional objects
+// are left out when empty. Documents of version 3.1 get 3.1 schemas.
+func (o OpenAPI) MarshalYAML() (interface{}, error) {
+	root := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	appendPair(&root, "openapi", stringNode(o.OpenAPI))
+
+	info := yaml.Node{Kind: yaml.MappingNode}
+	appendPair(&info, "title", stringNode(o.Info.Title))
+	if o.Info.Description != "" {
+		appendPair(&info, "description", stringNode(o.Info.Description))
+	}
+	appendPair(&info, "version", stringNode(o.Info.Version))
+	appendExtra(&info, o.Info.Extra)
+	appendPair(&root, "info", &info)
+
+	if o.ExternalDocs.URL != "" {
+		docs := yaml.Node{Kind: yaml.MappingNode}
+		if o.ExternalDocs.Description != "" {
+			appendPair(&docs, "description", stringNode(o.ExternalDocs.Description))
+		}
+		appendPair(&docs, "url", stringNode(o.ExternalDocs.URL))
+		appendPair(&root, "externalDocs", &docs)
+	}
+
+	if len(o.Servers) > 0 {
+		servers := yaml.Node{Kind: yaml.SequenceNode}
+		for _, server := range o.Servers {
+			item := yaml.Node{Kind: yaml.MappingNode}
+			appendPair(&item, "url", stringNode(server.URL))
+			if server.Description != "" {
+				appendPair(&item, "description", stringNode(server.Description))
+			}
+			appendExtra(&item, server.Extra)
+			servers.Content = append(servers.Content, &item)
+		}
+		appendPair(&root, "servers", &servers)
+	}
+
+	if len(o.Tags) > 0 {
+		tags := yaml.Node{Kind: yaml.SequenceNode}
+		for _, tag := range o.Tags {
+			item := yaml.Node{Kind: yaml.MappingNode}
+			appendPair(&item, "name", stringNode(tag.Name))
+			if tag.Description != "" {
+				appendPair(&item, "description", stringNode(tag.Description))
+			}
+			appendExtra(&item, tag.Extra)
+			tags.Content = append(tags.Content, &item)
+		}
+		appendPair(&root, "tags", &tags)
+	}
+
+	paths, err := o.Paths.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	appendPair(&root, "paths", paths.(*yaml.Node))
+
+	if len(o.Components.Schemas) > 0 || len(o.Components.SecuritySchemes) > 0 || o.Components.Extra != nil {
+		components, err := o.Components.MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		appendPair(&root, "components", components.(*yaml.Node))
+	}
+
+	if len(o.Security) > 0 {
+		security, err := marshalSecurity(o.Security)
+		if err != nil {
+			return nil, err
+		}
+		appendPair(&root, "security", security)
+	}
+	appendExtra(&root, o.Extra)
+
+	if strings.HasPrefix(o.OpenAPI, "3.1") {
+		walkSchemas(&root, upgradeSchema)
+	}
+	return &root, nil
+}
+
+// appendPair adds a key and its value to a mapping node.
+func appendPair(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// appendExtra writes back the fields kept by extraFields. They are copied, so rewriting the
+// marshaled document for 3.1 leaves the object unchanged.
+func appendExtra(node *yaml.Node, extra *yaml.Node) {
+	if extra == nil {
+		return
+	}
+	for _, item := range extra.Content {
+		node.Content = append(node.Content, cloneNode(item))
+	}
+}
+
+// cloneNode returns a deep copy of a node.
+func cloneNode(node *yaml.Node) *yaml.Node {
+	var clone = *node
+	clone.Content = nil
+	for _, item := range node.Content {
+		clone.Content = append(clone.Content, cloneNode(item))
+	}
+	return &clone
+}
+
+// stringNode returns a scalar which is always read back as a string, e.g. "200" or "1.0".
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// boolNode returns a boolean scalar.
+func boolNode(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}
 }
 
 // --------------------------
@@ -28,9 +162,10 @@ func (o *OpenAPI) GenerateYaml() ([]byte, error) {
 
 // Info contains metadata about the API.
 type Info struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description"`
-	Version     string `yaml:"version"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Version     string     `yaml:"version"`
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 // ExternalDocs provides links to external API documentation.
@@ -41,14 +176,16 @@ type ExternalDocs struct {
 
 // Server represents an API server.
 type Server struct {
-	URL         string `yaml:"url"`
-	Description string `yaml:"description"`
+	URL         string     `yaml:"url"`
+	Description string     `yaml:"description"`
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 // Tag categorizes API endpoints.
 type Tag struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 // --------------------------
@@ -89,6 +226,7 @@ func (p Paths) MarshalYAML() (interface{}, error) {
 		valueNode := yaml.Node{
 			Kind: yaml.MappingNode,
 		}
+		appendExtra(&valueNode, pi.Extra)
 
 		// Convert each operation into a methodName -> operationObject
 		for _, op := range pi.Operations {
@@ -119,6 +257,7 @@ func (p Paths) MarshalYAML() (interface{}, error) {
 type PathItem struct {
 	Path       string        // Not marshaled directly; used as map key
 	Operations []APIEndpoint `yaml:"operations"`
+	Extra      *yaml.Node    `yaml:"-"` // summary, description, servers or $ref of the path
 }
 
 // --------------------------
@@ -128,6 +267,7 @@ type PathItem struct {
 // APIEndpoint defines an API operation (method, summary, tags, etc.).
 type APIEndpoint struct {
 	Method      string         `yaml:"method"` // e.g., "GET", "POST"
+	OperationID string         `yaml:"operationId,omitempty"`
 	Summary     string         `yaml:"summary"`
 	Description string         `yaml:"description"`
 	Tags        []string       `yaml:"tags,omitempty"`
@@ -136,6 +276,7 @@ type APIEndpoint struct {
 	Responses   []Response     `yaml:"responses,omitempty"`
 	Security    []SecurityItem `yaml:"security,omitempty"`
 	Deprecated  bool           `yaml:"deprecated,omitempty"`
+	Extra       *yaml.Node     `yaml:"-"`
 }
 
 // marshalOperation converts an APIEndpoint into a YAML sub-map node
@@ -146,29 +287,16 @@ func marshalOperation(op APIEndpoint) (*yaml.Node, error) {
 		Kind: yaml.MappingNode,
 	}
 
-	// Add "summary: ..."
-	opNode.Content = append(opNode.Content,
-		&yaml.Node{
-			Kind:  yaml.ScalarNode,
-			Value: "summary",
-		},
-		&yaml.Node{
-			Kind:  yaml.ScalarNode,
-			Value: op.Summary,
-		},
-	)
-
-	// Add "description: ..."
-	opNode.Content = append(opNode.Content,
-		&yaml.Node{
-			Kind:  yaml.ScalarNode,
-			Value: "description",
-		},
-		&yaml.Node{
-			Kind:  yaml.ScalarNode,
-			Value: op.Description,
-		},
-	)
+	// Add "operationId", "summary" and "description" if present
+	if op.OperationID != "" {
+		appendPair(&opNode, "operationId", stringNode(op.OperationID))
+	}
+	if op.Summary != "" {
+		appendPair(&opNode, "summary", stringNode(op.Summary))
+	}
+	if op.Description != "" {
+		appendPair(&opNode, "description", stringNode(op.Description))
+	}
 
 	// Add "tags: [...]" if present
 	if len(op.Tags) > 0 {
@@ -176,10 +304,7 @@ func marshalOperation(op APIEndpoint) (*yaml.Node, error) {
 			Kind: yaml.SequenceNode,
 		}
 		for _, t := range op.Tags {
-			tagsNode.Content = append(tagsNode.Content, &yaml.Node{
-				Kind:  yaml.ScalarNode,
-				Value: t,
-			})
+			tagsNode.Content = append(tagsNode.Content, stringNode(t))
 		}
 		opNode.Content = append(opNode.Content,
 			&yaml.Node{
@@ -257,22 +382,24 @@ func marshalOperation(op APIEndpoint) (*yaml.Node, error) {
 				Kind:  yaml.ScalarNode,
 				Value: "deprecated",
 			},
-			&yaml.Node{
-				Kind:  yaml.ScalarNode,
-				Value: "true",
-			},
+			boolNode(true),
 		)
 	}
+	appendExtra(&opNode, op.Extra)
 
 	return &opNode, nil
 }
 
 type Parameter struct {
-	Name        string  `yaml:"name"`
-	In          string  `yaml:"in"`
-	Required    bool    `yaml:"required"`
-	Description string  `yaml:"description"`
-	Schema      *Schema `yaml:"schema"`
+	Name        string     `yaml:"name"`
+	In          string     `yaml:"in"`
+	Required    bool       `yaml:"required"`
+	Description string     `yaml:"description"`
+	Style       string     `yaml:"style,omitempty"`
+	Explode     bool       `yaml:"explode,omitempty"`
+	Schema      *Schema    `yaml:"schema"`
+	Ref         string     `yaml:"$ref,omitempty"` // a parameter of the components
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 func marshalParameters(params []Parameter) (*yaml.Node, error) {
@@ -282,31 +409,43 @@ func marshalParameters(params []Parameter) (*yaml.Node, error) {
 	}
 
 	for _, p := range params {
+		if p.Ref != "" {
+			paramsNode.Content = append(paramsNode.Content, refNode(p.Ref, p.Extra))
+			continue
+		}
 		paramNode := yaml.Node{
 			Kind: yaml.MappingNode,
 			Content: []*yaml.Node{
 				// name
 				{Kind: yaml.ScalarNode, Value: "name"},
-				{Kind: yaml.ScalarNode, Value: p.Name},
+				stringNode(p.Name),
 				// in
 				{Kind: yaml.ScalarNode, Value: "in"},
-				{Kind: yaml.ScalarNode, Value: p.In},
-				// required
-				{Kind: yaml.ScalarNode, Value: "required"},
-				{Kind: yaml.ScalarNode, Value: fmt.Sprintf("%v", p.Required)},
-				// description
-				{Kind: yaml.ScalarNode, Value: "description"},
-				{Kind: yaml.ScalarNode, Value: p.Description},
-				// schema
-				{Kind: yaml.ScalarNode, Value: "schema"},
+				stringNode(p.In),
 			},
 		}
+		// description, required and schema; path parameters are always required
+		if p.Description != "" {
+			appendPair(&paramNode, "description", stringNode(p.Description))
+		}
+		if p.Required || p.In == "path" {
+			appendPair(&paramNode, "required", boolNode(true))
+		}
+		if p.Style != "" {
+			appendPair(&paramNode, "style", stringNode(p.Style))
+		}
+		if p.Explode {
+			appendPair(&paramNode, "explode", boolNode(true))
+		}
 
-		schemaNode, err := marshalSchema(p.Schema)
-		if err != nil {
-			return nil, err
+		if p.Schema != nil {
+			schemaNode, err := marshalSchema(p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			appendPair(&paramNode, "schema", schemaNode)
 		}
-		paramNode.Content = append(paramNode.Content, schemaNode)
+		appendExtra(&paramNode, p.Extra)
 
 		paramsNode.Content = append(paramsNode.Content, &paramNode)
 	}
@@ -317,31 +456,39 @@ func marshalParameters(params []Parameter) (*yaml.Node, error) {
 type RequestBody struct {
 	Description string               `yaml:"description"`
 	Content     []RequestContentType `yaml:"content"`
+	Required    bool                 `yaml:"required,omitempty"`
+	Ref         string               `yaml:"$ref,omitempty"`
+	Extra       *yaml.Node           `yaml:"-"`
 }
 
 type RequestContentType struct {
-	ContentType string  `yaml:"contentType"`
-	Schema      *Schema `yaml:"schema"`
+	ContentType string     `yaml:"contentType"`
+	Schema      *Schema    `yaml:"schema"`
+	Example     *yaml.Node `yaml:"example,omitempty"`
+	Extra       *yaml.Node `yaml:"-"` // examples and encoding
 }
 
 func marshalRequestBody(rb *RequestBody) (*yaml.Node, error) {
-	// We'll create a map with "description" + "content"
+	if rb.Ref != "" {
+		return refNode(rb.Ref, rb.Extra), nil
+	}
+	// We'll create a map with "description", "content" and "required"
 	rbNode := yaml.Node{
 		Kind: yaml.MappingNode,
-		Content: []*yaml.Node{
-			// description
-			{Kind: yaml.ScalarNode, Value: "description"},
-			{Kind: yaml.ScalarNode, Value: rb.Description},
-			// content
-			{Kind: yaml.ScalarNode, Value: "content"},
-		},
+	}
+	if rb.Description != "" {
+		appendPair(&rbNode, "description", stringNode(rb.Description))
 	}
 
 	contentNode, err := marshalRequestContent(rb.Content)
 	if err != nil {
 		return nil, err
 	}
-	rbNode.Content = append(rbNode.Content, contentNode)
+	appendPair(&rbNode, "content", contentNode)
+	if rb.Required {
+		appendPair(&rbNode, "required", boolNode(true))
+	}
+	appendExtra(&rbNode, rb.Extra)
 
 	return &rbNode, nil
 }
@@ -360,40 +507,68 @@ func marshalRequestContent(rcs []RequestContentType) (*yaml.Node, error) {
 		}
 
 		// value = sub-map { schema: ... }
-		valueNode := yaml.Node{
-			Kind: yaml.MappingNode,
-			Content: []*yaml.Node{
-				{Kind: yaml.ScalarNode, Value: "schema"},
-			},
-		}
-		schemaNode, err := marshalSchema(rc.Schema)
+		valueNode, err := marshalMedia(rc.Schema, rc.Example, rc.Extra)
 		if err != nil {
 			return nil, err
 		}
-		valueNode.Content = append(valueNode.Content, schemaNode)
 
-		contentMap.Content = append(contentMap.Content, &keyNode, &valueNode)
+		contentMap.Content = append(contentMap.Content, &keyNode, valueNode)
 	}
 
 	return &contentMap, nil
 }
 
+// marshalMedia emits a media type object.
+func marshalMedia(schema *Schema, example *yaml.Node, extra *yaml.Node) (*yaml.Node, error) {
+	node := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	if schema != nil {
+		schemaNode, err := marshalSchema(schema)
+		if err != nil {
+			return nil, err
+		}
+		appendPair(&node, "schema", schemaNode)
+	}
+	if example != nil {
+		appendPair(&node, "example", example)
+	}
+	appendExtra(&node, extra)
+	return &node, nil
+}
+
+// refNode emits a reference object, with the fields kept next to $ref.
+func refNode(ref string, extra *yaml.Node) *yaml.Node {
+	node := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	appendPair(&node, "$ref", &yaml.Node{Kind: yaml.ScalarNode, Value: ref, Style: yaml.SingleQuotedStyle})
+	appendExtra(&node, extra)
+	return &node
+}
+
 type Response struct {
 	StatusCode  string                `yaml:"statusCode"` // e.g. "200"
 	Description string                `yaml:"description"`
 	Content     []ResponseContentType `yaml:"content,omitempty"`
 	Headers     []Header              `yaml:"headers,omitempty"`
+	Ref         string                `yaml:"$ref,omitempty"`
+	Extra       *yaml.Node            `yaml:"-"` // links
 }
 
 type ResponseContentType struct {
-	ContentType string  `yaml:"contentType"`
-	Schema      *Schema `yaml:"schema,omitempty"`
+	ContentType string     `yaml:"contentType"`
+	Schema      *Schema    `yaml:"schema,omitempty"`
+	Example     *yaml.Node `yaml:"example,omitempty"`
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 type Header struct {
-	Name        string  `yaml:"name"`
-	Description string  `yaml:"description"`
-	Schema      *Schema `yaml:"schema"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Schema      *Schema    `yaml:"schema"`
+	Ref         string     `yaml:"$ref,omitempty"`
+	Extra       *yaml.Node `yaml:"-"`
 }
 
 func marshalResponses(resps []Response) (*yaml.Node, error) {
@@ -403,29 +578,28 @@ func marshalResponses(resps []Response) (*yaml.Node, error) {
 	}
 
 	for _, r := range resps {
-		keyNode := yaml.Node{
-			Kind:  yaml.ScalarNode,
-			Value: r.StatusCode, // e.g., "200"
-		}
-
+		// status codes are strings, "200" must not be read back as a number
 		valNode, err := marshalSingleResponse(r)
 		if err != nil {
 			return nil, err
 		}
 
-		root.Content = append(root.Content, &keyNode, valNode)
+		root.Content = append(root.Content, stringNode(r.StatusCode), valNode)
 	}
 
 	return &root, nil
 }
 
 func marshalSingleResponse(r Response) (*yaml.Node, error) {
+	if r.Ref != "" {
+		return refNode(r.Ref, r.Extra), nil
+	}
 	respNode := yaml.Node{
 		Kind: yaml.MappingNode,
 		Content: []*yaml.Node{
 			// description
 			{Kind: yaml.ScalarNode, Value: "description"},
-			{Kind: yaml.ScalarNode, Value: r.Description},
+			stringNode(r.Description),
 		},
 	}
 
@@ -439,18 +613,11 @@ func marshalSingleResponse(r Response) (*yaml.Node, error) {
 				Kind:  yaml.ScalarNode,
 				Value: c.ContentType,
 			}
-			ctVal := yaml.Node{
-				Kind: yaml.MappingNode,
-				Content: []*yaml.Node{
-					{Kind: yaml.ScalarNode, Value: "schema"},
-				},
-			}
-			schemaNode, err := marshalSchema(c.Schema)
+			ctVal, err := marshalMedia(c.Schema, c.Example, c.Extra)
 			if err != nil {
 				return nil, err
 			}
-			ctVal.Content = append(ctVal.Content, schemaNode)
-			contentMap.Content = append(contentMap.Content, &ctKey, &ctVal)
+			contentMap.Content = append(contentMap.Content, &ctKey, ctVal)
 		}
 
 		respNode.Content = append(respNode.Content,
@@ -472,19 +639,24 @@ func marshalSingleResponse(r Response) (*yaml.Node, error) {
 				Kind:  yaml.ScalarNode,
 				Value: h.Name,
 			}
+			if h.Ref != "" {
+				headersMap.Content = append(headersMap.Content, &hKey, refNode(h.Ref, h.Extra))
+				continue
+			}
 			hVal := yaml.Node{
 				Kind: yaml.MappingNode,
-				Content: []*yaml.Node{
-					{Kind: yaml.ScalarNode, Value: "description"},
-					{Kind: yaml.ScalarNode, Value: h.Description},
-					{Kind: yaml.ScalarNode, Value: "schema"},
-				},
 			}
-			schemaNode, err := marshalSchema(h.Schema)
-			if err != nil {
-				return nil, err
+			if h.Description != "" {
+				appendPair(&hVal, "description", stringNode(h.Description))
+			}
+			if h.Schema != nil {
+				schemaNode, err := marshalSchema(h.Schema)
+				if err != nil {
+					return nil, err
+				}
+				appendPair(&hVal, "schema", schemaNode)
 			}
-			hVal.Content = append(hVal.Content, schemaNode)
+			appendExtra(&hVal, h.Extra)
 			headersMap.Content = append(headersMap.Content, &hKey, &hVal)
 		}
 
@@ -496,22 +668,46 @@ func marshalSingleResponse(r Response) (*yaml.Node, error) {
 			&headersMap,
 		)
 	}
+	appendExtra(&respNode, r.Extra)
 
 	return &respNode, nil
 }
 
 type Schema struct {
-	Type        string           `yaml:"type,omitempty"`
-	Properties  []SchemaProperty `yaml:"properties,omitempty"`
-	Items       *Schema          `yaml:"items,omitempty"`
-	Required    []string         `yaml:"required,omitempty"`
-	Description string           `yaml:"description,omitempty"`
+	Type                 string           `yaml:"type,omitempty"`
+	Format               string           `yaml:"format,omitempty"`
+	Properties           []SchemaProperty `yaml:"properties,omitempty"`
+	Items                *Schema          `yaml:"items,omitempty"`
+	AdditionalProperties *Schema          `yaml:"additionalProperties,omitempty"`
+	Required             []string         `yaml:"required,omitempty"`
+	Description          string           `yaml:"description,omitempty"`
+	Enum                 []string         `yaml:"enum,omitempty"`
+	Pattern              string           `yaml:"pattern,omitempty"`
+	MinLength            *int             `yaml:"minLength,omitempty"`
+	MaxLength            *int             `yaml:"maxLength,omitempty"`
+	Minimum              *float64         `yaml:"minimum,omitempty"`
+	Maximum              *float64         `yaml:"maximum,omitempty"`
+	ExclusiveMinimum     bool             `yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool             `yaml:"exclusiveMaximum,omitempty"`
+	Nullable             bool             `yaml:"nullable,omitempty"`
+	Example              *yaml.Node       `yaml:"example,omitempty"`
+	// Ref points to a component schema, e.g. #/components/schemas/models.User
+	Ref   string    `yaml:"$ref,omitempty"`
+	AllOf []*Schema `yaml:"allOf,omitempty"`
+	OneOf []*Schema `yaml:"oneOf,omitempty"`
+	// Extra holds keywords that are not modeled, e.g. oneOf, default or readOnly
+	Extra *yaml.Node `yaml:"-"`
+}
+
+// SchemaRef returns a schema referencing the component schema with the given name.
+func SchemaRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
 }
 
+// SchemaProperty is a named schema inside the properties of an object schema.
 type SchemaProperty struct {
-	Name        string `yaml:"name"`
-	Type        string `yaml:"type"`
-	Description string `yaml:"description"`
+	Name   string `yaml:"name"`
+	Schema `yaml:",inline"`
 }
 
 func marshalSchema(s *Schema) (*yaml.Node, error) {
@@ -522,6 +718,48 @@ func marshalSchema(s *Schema) (*yaml.Node, error) {
 	if s == nil {
 		return &schemaNode, nil
 	}
+	if s.Ref != "" {
+		// siblings of $ref are ignored in 3.0, allOf carries a reference with a description;
+		// the ones of a hand-written schema are kept
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "$ref"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Ref, Style: yaml.SingleQuotedStyle},
+		)
+		if s.Description != "" {
+			appendPair(&schemaNode, "description", stringNode(s.Description))
+		}
+		appendExtra(&schemaNode, s.Extra)
+		return &schemaNode, nil
+	}
+	if len(s.AllOf) > 0 {
+		allOfNode := yaml.Node{
+			Kind: yaml.SequenceNode,
+		}
+		for _, item := range s.AllOf {
+			child, err := marshalSchema(item)
+			if err != nil {
+				return nil, err
+			}
+			allOfNode.Content = append(allOfNode.Content, child)
+		}
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "allOf"},
+			&allOfNode,
+		)
+	}
+	if len(s.OneOf) > 0 {
+		oneOfNode := yaml.Node{
+			Kind: yaml.SequenceNode,
+		}
+		for _, item := range s.OneOf {
+			child, err := marshalSchema(item)
+			if err != nil {
+				return nil, err
+			}
+			oneOfNode.Content = append(oneOfNode.Content, child)
+		}
+		appendPair(&schemaNode, "oneOf", &oneOfNode)
+	}
 	if s.Type != "" {
 		schemaNode.Content = append(schemaNode.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Value: "type"},
@@ -529,41 +767,99 @@ func marshalSchema(s *Schema) (*yaml.Node, error) {
 		)
 	}
 
+	if s.Format != "" {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "format"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Format},
+		)
+	}
+
 	if s.Description != "" {
 		schemaNode.Content = append(schemaNode.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Value: "description"},
-			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Description},
+			stringNode(s.Description),
 		)
 	}
 
-	if len(s.Properties) > 0 {
-		propsNode := yaml.Node{
-			Kind: yaml.MappingNode,
+	if s.Nullable {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "nullable"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+		)
+	}
+
+	if s.Pattern != "" {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "pattern"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Pattern, Style: yaml.SingleQuotedStyle},
+		)
+	}
+
+	if s.MinLength != nil {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "minLength"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(*s.MinLength)},
+		)
+	}
+
+	if s.MaxLength != nil {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "maxLength"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(*s.MaxLength)},
+		)
+	}
+
+	if s.Minimum != nil {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "minimum"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(*s.Minimum, 'f', -1, 64)},
+		)
+		if s.ExclusiveMinimum {
+			schemaNode.Content = append(schemaNode.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "exclusiveMinimum"},
+				boolNode(true),
+			)
 		}
-		for _, sp := range s.Properties {
-			propKeyNode := yaml.Node{
-				Kind:  yaml.ScalarNode,
-				Value: sp.Name,
-			}
-			propValNode := yaml.Node{
-				Kind: yaml.MappingNode,
-				Content: []*yaml.Node{
-					{Kind: yaml.ScalarNode, Value: "type"},
-					{Kind: yaml.ScalarNode, Value: sp.Type},
-				},
-			}
-			if sp.Description != "" {
-				propValNode.Content = append(propValNode.Content,
-					&yaml.Node{Kind: yaml.ScalarNode, Value: "description"},
-					&yaml.Node{Kind: yaml.ScalarNode, Value: sp.Description},
-				)
+	}
+
+	if s.Maximum != nil {
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "maximum"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(*s.Maximum, 'f', -1, 64)},
+		)
+		if s.ExclusiveMaximum {
+			schemaNode.Content = append(schemaNode.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "exclusiveMaximum"},
+				boolNode(true),
+			)
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		enumNode := yaml.Node{
+			Kind: yaml.SequenceNode,
+		}
+		for _, v := range s.Enum {
+			var value = yaml.Node{Kind: yaml.ScalarNode, Value: v}
+			if s.Type == "string" {
+				value.Style = yaml.DoubleQuotedStyle
 			}
-			propsNode.Content = append(propsNode.Content, &propKeyNode, &propValNode)
+			enumNode.Content = append(enumNode.Content, &value)
 		}
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "enum"},
+			&enumNode,
+		)
+	}
 
+	if len(s.Properties) > 0 {
+		propsNode, err := marshalProperties(s.Properties)
+		if err != nil {
+			return nil, err
+		}
 		schemaNode.Content = append(schemaNode.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Value: "properties"},
-			&propsNode,
+			propsNode,
 		)
 	}
 
@@ -578,6 +874,17 @@ func marshalSchema(s *Schema) (*yaml.Node, error) {
 		schemaNode.Content = append(schemaNode.Content, child)
 	}
 
+	if s.AdditionalProperties != nil {
+		child, err := marshalSchema(s.AdditionalProperties)
+		if err != nil {
+			return nil, err
+		}
+		schemaNode.Content = append(schemaNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "additionalProperties"},
+			child,
+		)
+	}
+
 	if len(s.Required) > 0 {
 		reqNode := yaml.Node{
 			Kind: yaml.SequenceNode,
@@ -595,21 +902,163 @@ func marshalSchema(s *Schema) (*yaml.Node, error) {
 		)
 	}
 
+	if s.Example != nil {
+		appendPair(&schemaNode, "example", s.Example)
+	}
+	appendExtra(&schemaNode, s.Extra)
+
 	return &schemaNode, nil
 }
 
+// marshalProperties emits a YAML map of property name to property schema,
+// descending into nested objects, array items and map values.
+func marshalProperties(properties []SchemaProperty) (*yaml.Node, error) {
+	propsNode := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	for _, sp := range properties {
+		propKeyNode := yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Value: sp.Name,
+		}
+		propValNode, err := marshalSchema(&sp.Schema)
+		if err != nil {
+			return nil, err
+		}
+		propsNode.Content = append(propsNode.Content, &propKeyNode, propValNode)
+	}
+	return &propsNode, nil
+}
+
 type Components struct {
 	SecuritySchemes []SecurityScheme `yaml:"securitySchemes,omitempty"`
 	Schemas         []SchemaItem     `yaml:"schemas,omitempty"`
+	// Extra holds the responses, parameters, examples, request bodies, headers and other
+	// components of a hand-written document
+	Extra *yaml.Node `yaml:"-"`
+}
+
+// AddSchema adds a component schema, or replaces the one with the same name in place.
+func (c *Components) AddSchema(name string, schema Schema) {
+	for i := range c.Schemas {
+		if c.Schemas[i].Name == name {
+			c.Schemas[i].Schema = schema
+			return
+		}
+	}
+	c.Schemas = append(c.Schemas, SchemaItem{Name: name, Schema: schema})
+}
+
+// Schema returns the component schema with the given name.
+func (c *Components) Schema(name string) (*Schema, bool) {
+	for i := range c.Schemas {
+		if c.Schemas[i].Name == name {
+			return &c.Schemas[i].Schema, true
+		}
+	}
+	return nil, false
 }
 
+// MarshalYAML emits the security schemes and schemas as maps keyed by name, in the order they were added.
+func (c Components) MarshalYAML() (interface{}, error) {
+	root := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	if len(c.SecuritySchemes) > 0 {
+		schemes := yaml.Node{
+			Kind: yaml.MappingNode,
+		}
+		for _, scheme := range c.SecuritySchemes {
+			child, err := marshalSecurityScheme(scheme)
+			if err != nil {
+				return nil, err
+			}
+			appendPair(&schemes, scheme.Name, child)
+		}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "securitySchemes"},
+			&schemes,
+		)
+	}
+	if len(c.Schemas) > 0 {
+		schemas := yaml.Node{
+			Kind: yaml.MappingNode,
+		}
+		for i := range c.Schemas {
+			child, err := marshalSchema(&c.Schemas[i].Schema)
+			if err != nil {
+				return nil, err
+			}
+			schemas.Content = append(schemas.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: c.Schemas[i].Name},
+				child,
+			)
+		}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "schemas"},
+			&schemas,
+		)
+	}
+	appendExtra(&root, c.Extra)
+	return &root, nil
+}
+
+// SecurityScheme is a component security scheme, Name is its key in components.securitySchemes.
 type SecurityScheme struct {
-	Name         string `yaml:"name"`
-	Type         string `yaml:"type"`
-	Description  string `yaml:"description,omitempty"`
-	In           string `yaml:"in,omitempty"`
-	Scheme       string `yaml:"scheme,omitempty"`
-	BearerFormat string `yaml:"bearerFormat,omitempty"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"` // apiKey, http, oauth2 or openIdConnect
+	Description string `yaml:"description,omitempty"`
+	// ParamName is the header, query or cookie parameter of apiKey schemes
+	ParamName        string      `yaml:"paramName,omitempty"`
+	In               string      `yaml:"in,omitempty"`
+	Scheme           string      `yaml:"scheme,omitempty"`
+	BearerFormat     string      `yaml:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `yaml:"flows,omitempty"`
+	OpenIdConnectURL string      `yaml:"openIdConnectUrl,omitempty"`
+	Extra            *yaml.Node  `yaml:"-"`
+}
+
+// OAuthFlows are the flows of an oauth2 security scheme.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `yaml:"implicit,omitempty"`
+	Password          *OAuthFlow `yaml:"password,omitempty"`
+	ClientCredentials *OAuthFlow `yaml:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode,omitempty"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
+	RefreshURL       string            `yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes"`
+}
+
+func marshalSecurityScheme(scheme SecurityScheme) (*yaml.Node, error) {
+	schemeNode := yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	appendPair(&schemeNode, "type", stringNode(scheme.Type))
+	for _, pair := range [][2]string{
+		{"description", scheme.Description},
+		{"name", scheme.ParamName},
+		{"in", scheme.In},
+		{"scheme", scheme.Scheme},
+		{"bearerFormat", scheme.BearerFormat},
+		{"openIdConnectUrl", scheme.OpenIdConnectURL},
+	} {
+		if pair[1] != "" {
+			appendPair(&schemeNode, pair[0], stringNode(pair[1]))
+		}
+	}
+	if scheme.Flows != nil {
+		var flows yaml.Node
+		if err := flows.Encode(scheme.Flows); err != nil {
+			return nil, err
+		}
+		appendPair(&schemeNode, "flows", &flows)
+	}
+	appendExtra(&schemeNode, scheme.Extra)
+	return &schemeNode, nil
 }
 
 type SchemaItem struct {
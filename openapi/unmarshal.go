@@ -0,0 +1,429 @@
+package openapi
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalYAML reads the document; fields that are not modeled are kept in Extra.
+func (o *OpenAPI) UnmarshalYAML(node *yaml.Node) error {
+	type plain OpenAPI
+	if err := node.Decode((*plain)(o)); err != nil {
+		return err
+	}
+	o.Extra = extraFields(node, "openapi", "info", "externalDocs", "servers", "tags", "paths", "components", "security")
+	return nil
+}
+
+// UnmarshalYAML reads the info object, keeping contact, license and the like in Extra.
+func (i *Info) UnmarshalYAML(node *yaml.Node) error {
+	type plain Info
+	if err := node.Decode((*plain)(i)); err != nil {
+		return err
+	}
+	i.Extra = extraFields(node, "title", "description", "version")
+	return nil
+}
+
+// UnmarshalYAML reads a server, keeping its variables in Extra.
+func (s *Server) UnmarshalYAML(node *yaml.Node) error {
+	type plain Server
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.Extra = extraFields(node, "url", "description")
+	return nil
+}
+
+// UnmarshalYAML reads a tag, keeping its externalDocs in Extra.
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error {
+	type plain Tag
+	if err := node.Decode((*plain)(t)); err != nil {
+		return err
+	}
+	t.Extra = extraFields(node, "name", "description")
+	return nil
+}
+
+// UnmarshalYAML reads the paths map; parameters shared by a path are added to each operation.
+func (p *Paths) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: paths must be a map", node.Line)
+	}
+	var err error
+	eachPair(node, func(path string, item *yaml.Node) {
+		if err != nil {
+			return
+		}
+		var pathItem = PathItem{Path: path, Extra: extraFields(item, pathItemFields...)}
+		var shared []Parameter
+		if params := value(item, "parameters"); params != nil {
+			if err = params.Decode(&shared); err != nil {
+				return
+			}
+		}
+		eachPair(item, func(method string, operation *yaml.Node) {
+			if err != nil || !methods[method] {
+				return
+			}
+			var op APIEndpoint
+			if err = operation.Decode(&op); err != nil {
+				return
+			}
+			op.Method = strings.ToUpper(method)
+			for _, param := range shared {
+				if !hasParameter(op.Parameters, param) {
+					op.Parameters = append(op.Parameters, param)
+				}
+			}
+			pathItem.Operations = append(pathItem.Operations, op)
+		})
+		p.AddPath(&pathItem)
+	})
+	return err
+}
+
+// pathItemFields are the fields of a path item read into PathItem.
+var pathItemFields = []string{"parameters", "get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// UnmarshalYAML reads an operation with its responses map.
+func (op *APIEndpoint) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		OperationID string         `yaml:"operationId"`
+		Summary     string         `yaml:"summary"`
+		Description string         `yaml:"description"`
+		Tags        []string       `yaml:"tags"`
+		Parameters  []Parameter    `yaml:"parameters"`
+		RequestBody *RequestBody   `yaml:"requestBody"`
+		Responses   yaml.Node      `yaml:"responses"`
+		Security    []SecurityItem `yaml:"security"`
+		Deprecated  bool           `yaml:"deprecated"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*op = APIEndpoint{
+		OperationID: raw.OperationID,
+		Summary:     raw.Summary,
+		Description: raw.Description,
+		Tags:        raw.Tags,
+		Parameters:  raw.Parameters,
+		RequestBody: raw.RequestBody,
+		Security:    raw.Security,
+		Deprecated:  raw.Deprecated,
+		Extra: extraFields(node, "operationId", "summary", "description", "tags", "parameters", "requestBody",
+			"responses", "security", "deprecated"),
+	}
+	var err error
+	eachPair(&raw.Responses, func(status string, item *yaml.Node) {
+		if err != nil {
+			return
+		}
+		var response Response
+		if err = item.Decode(&response); err == nil {
+			response.StatusCode = status
+			op.Responses = append(op.Responses, response)
+		}
+	})
+	return err
+}
+
+// UnmarshalYAML reads a parameter, or a reference to one of the components.
+func (p *Parameter) UnmarshalYAML(node *yaml.Node) error {
+	type plain Parameter
+	if err := node.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.Extra = extraFields(node, "name", "in", "required", "description", "style", "explode", "schema", "$ref")
+	return nil
+}
+
+// UnmarshalYAML reads a request body with its content map.
+func (rb *RequestBody) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Description string    `yaml:"description"`
+		Content     yaml.Node `yaml:"content"`
+		Required    bool      `yaml:"required"`
+		Ref         string    `yaml:"$ref"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*rb = RequestBody{
+		Description: raw.Description,
+		Required:    raw.Required,
+		Ref:         raw.Ref,
+		Extra:       extraFields(node, "description", "content", "required", "$ref"),
+	}
+	var err error
+	eachPair(&raw.Content, func(contentType string, media *yaml.Node) {
+		var item RequestContentType
+		if err == nil {
+			item.Schema, item.Example, item.Extra, err = decodeMedia(media)
+			item.ContentType = contentType
+			rb.Content = append(rb.Content, item)
+		}
+	})
+	return err
+}
+
+// UnmarshalYAML reads a response with its content and headers maps.
+func (r *Response) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Description string    `yaml:"description"`
+		Content     yaml.Node `yaml:"content"`
+		Headers     yaml.Node `yaml:"headers"`
+		Ref         string    `yaml:"$ref"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*r = Response{
+		Description: raw.Description,
+		Ref:         raw.Ref,
+		Extra:       extraFields(node, "description", "content", "headers", "$ref"),
+	}
+	var err error
+	eachPair(&raw.Content, func(contentType string, media *yaml.Node) {
+		var item ResponseContentType
+		if err == nil {
+			item.Schema, item.Example, item.Extra, err = decodeMedia(media)
+			item.ContentType = contentType
+			r.Content = append(r.Content, item)
+		}
+	})
+	eachPair(&raw.Headers, func(name string, item *yaml.Node) {
+		var header Header
+		if err == nil {
+			err = item.Decode(&header)
+			header.Name = name
+			r.Headers = append(r.Headers, header)
+		}
+	})
+	return err
+}
+
+// UnmarshalYAML reads a header, or a reference to one of the components.
+func (h *Header) UnmarshalYAML(node *yaml.Node) error {
+	type plain Header
+	if err := node.Decode((*plain)(h)); err != nil {
+		return err
+	}
+	h.Extra = extraFields(node, "description", "schema", "$ref")
+	return nil
+}
+
+// decodeMedia reads the schema and example of a media type object, and its other fields.
+func decodeMedia(media *yaml.Node) (*Schema, *yaml.Node, *yaml.Node, error) {
+	var schema *Schema
+	if node := value(media, "schema"); node != nil {
+		schema = &Schema{}
+		if err := node.Decode(schema); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return schema, value(media, "example"), extraFields(media, "schema", "example"), nil
+}
+
+// UnmarshalYAML reads a 3.0 or 3.1 schema: the properties map, $ref, type lists with null and
+// numeric exclusive bounds.
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Type                 yaml.Node  `yaml:"type"`
+		Format               string     `yaml:"format"`
+		Properties           yaml.Node  `yaml:"properties"`
+		Items                *Schema    `yaml:"items"`
+		AdditionalProperties yaml.Node  `yaml:"additionalProperties"`
+		Required             []string   `yaml:"required"`
+		Description          string     `yaml:"description"`
+		Enum                 []string   `yaml:"enum"`
+		Pattern              string     `yaml:"pattern"`
+		MinLength            *int       `yaml:"minLength"`
+		MaxLength            *int       `yaml:"maxLength"`
+		Minimum              *float64   `yaml:"minimum"`
+		Maximum              *float64   `yaml:"maximum"`
+		ExclusiveMinimum     yaml.Node  `yaml:"exclusiveMinimum"`
+		ExclusiveMaximum     yaml.Node  `yaml:"exclusiveMaximum"`
+		Nullable             bool       `yaml:"nullable"`
+		Example              *yaml.Node `yaml:"example"`
+		Ref                  string     `yaml:"$ref"`
+		AllOf                []*Schema  `yaml:"allOf"`
+		OneOf                []*Schema  `yaml:"oneOf"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*s = Schema{
+		Format:      raw.Format,
+		Items:       raw.Items,
+		Required:    raw.Required,
+		Description: raw.Description,
+		Enum:        raw.Enum,
+		Pattern:     raw.Pattern,
+		MinLength:   raw.MinLength,
+		MaxLength:   raw.MaxLength,
+		Minimum:     raw.Minimum,
+		Maximum:     raw.Maximum,
+		Nullable:    raw.Nullable,
+		Example:     raw.Example,
+		Ref:         raw.Ref,
+		AllOf:       raw.AllOf,
+		OneOf:       raw.OneOf,
+		Extra:       extraFields(node, schemaFields...),
+	}
+	switch raw.Type.Kind {
+	case yaml.ScalarNode:
+		s.Type = raw.Type.Value
+	case yaml.SequenceNode:
+		for _, item := range raw.Type.Content {
+			if item.Value == "null" {
+				s.Nullable = true
+			} else {
+				s.Type = item.Value
+			}
+		}
+	}
+	var err error
+	eachPair(&raw.Properties, func(name string, item *yaml.Node) {
+		var property = SchemaProperty{Name: name}
+		if err == nil {
+			err = item.Decode(&property.Schema)
+			s.Properties = append(s.Properties, property)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	switch raw.AdditionalProperties.Kind {
+	case yaml.MappingNode:
+		s.AdditionalProperties = &Schema{}
+		if err = raw.AdditionalProperties.Decode(s.AdditionalProperties); err != nil {
+			return err
+		}
+	case yaml.ScalarNode:
+		// additionalProperties: false is kept as written
+		if s.Extra == nil {
+			s.Extra = &yaml.Node{Kind: yaml.MappingNode}
+		}
+		appendPair(s.Extra, "additionalProperties", &raw.AdditionalProperties)
+	}
+	s.ExclusiveMinimum = exclusiveBound(&raw.ExclusiveMinimum, &s.Minimum)
+	s.ExclusiveMaximum = exclusiveBound(&raw.ExclusiveMaximum, &s.Maximum)
+	return nil
+}
+
+// schemaFields are the keywords read into Schema.
+var schemaFields = []string{"type", "format", "properties", "items", "additionalProperties", "required", "description",
+	"enum", "pattern", "minLength", "maxLength", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "nullable",
+	"example", "$ref", "allOf", "oneOf"}
+
+// exclusiveBound reads a boolean 3.0 exclusive bound, or a 3.1 one which replaces the bound.
+func exclusiveBound(node *yaml.Node, bound **float64) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+	if node.ShortTag() == "!!bool" {
+		return node.Value == "true"
+	}
+	if n, err := strconv.ParseFloat(node.Value, 64); err == nil {
+		*bound = &n
+		return true
+	}
+	return false
+}
+
+// UnmarshalYAML reads the securitySchemes and schemas maps; the other components are kept in Extra.
+func (c *Components) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		SecuritySchemes yaml.Node `yaml:"securitySchemes"`
+		Schemas         yaml.Node `yaml:"schemas"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*c = Components{Extra: extraFields(node, "securitySchemes", "schemas")}
+	var err error
+	eachPair(&raw.SecuritySchemes, func(name string, item *yaml.Node) {
+		var scheme SecurityScheme
+		if err == nil {
+			err = item.Decode(&scheme)
+			scheme.Name = name
+			c.SecuritySchemes = append(c.SecuritySchemes, scheme)
+		}
+	})
+	eachPair(&raw.Schemas, func(name string, item *yaml.Node) {
+		var schema Schema
+		if err == nil {
+			err = item.Decode(&schema)
+			c.AddSchema(name, schema)
+		}
+	})
+	return err
+}
+
+// UnmarshalYAML reads a security scheme, whose name is the parameter name of apiKey schemes.
+func (s *SecurityScheme) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Type             string      `yaml:"type"`
+		Description      string      `yaml:"description"`
+		Name             string      `yaml:"name"`
+		In               string      `yaml:"in"`
+		Scheme           string      `yaml:"scheme"`
+		BearerFormat     string      `yaml:"bearerFormat"`
+		Flows            *OAuthFlows `yaml:"flows"`
+		OpenIdConnectURL string      `yaml:"openIdConnectUrl"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*s = SecurityScheme{
+		Type:             raw.Type,
+		Description:      raw.Description,
+		ParamName:        raw.Name,
+		In:               raw.In,
+		Scheme:           raw.Scheme,
+		BearerFormat:     raw.BearerFormat,
+		Flows:            raw.Flows,
+		OpenIdConnectURL: raw.OpenIdConnectURL,
+		Extra:            extraFields(node, "type", "description", "name", "in", "scheme", "bearerFormat", "flows", "openIdConnectUrl"),
+	}
+	return nil
+}
+
+// UnmarshalYAML reads a security requirement of a single scheme, as in `- ApiKeyAuth: []`.
+func (s *SecurityItem) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return fmt.Errorf("line %d: security requirements must name exactly one scheme", node.Line)
+	}
+	s.Name = node.Content[0].Value
+	return node.Content[1].Decode(&s.Scopes)
+}
+
+// extraFields returns the pairs of a mapping node whose keys are not in known, or nil when
+// there are none, so a hand-written document is written back without losing fields.
+func extraFields(node *yaml.Node, known ...string) *yaml.Node {
+	var extra = yaml.Node{Kind: yaml.MappingNode}
+	eachPair(node, func(key string, item *yaml.Node) {
+		for _, name := range known {
+			if name == key {
+				return
+			}
+		}
+		extra.Content = append(extra.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, item)
+	})
+	if len(extra.Content) == 0 {
+		return nil
+	}
+	return &extra
+}
+
+func hasParameter(params []Parameter, param Parameter) bool {
+	for _, item := range params {
+		if item.Name == param.Name && item.In == param.In && item.Ref == param.Ref {
+			return true
+		}
+	}
+	return false
+}
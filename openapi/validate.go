@@ -0,0 +1,664 @@
+package openapi
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"strings"
+)
+
+// StructureError lists the problems found by CheckStructure, each prefixed by its location.
+type StructureError struct {
+	Problems []string
+}
+
+func (e *StructureError) Error() string {
+	return "malformed OpenAPI document: " + strings.Join(e.Problems, "; ")
+}
+
+var (
+	versionPattern   = regexp.MustCompile(`^3\.[01]\.\d+$`)
+	statusPattern    = regexp.MustCompile(`^(default|[1-5](\d\d|XX))$`)
+	componentPattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+	templatePattern  = regexp.MustCompile(`{([^}]+)}`)
+	methods          = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true}
+	locations        = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+	schemeTypes      = map[string]bool{"apiKey": true, "http": true, "oauth2": true, "openIdConnect": true, "mutualTLS": true}
+	schemaTypes      = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true}
+
+	// styles lists the serialization styles allowed for each parameter location.
+	styles = map[string]map[string]bool{
+		"path":   {"matrix": true, "label": true, "simple": true},
+		"query":  {"form": true, "spaceDelimited": true, "pipeDelimited": true, "deepObject": true},
+		"header": {"simple": true},
+		"cookie": {"form": true},
+	}
+)
+
+// keywords lists the fields of an object kind in 3.0; version31 adds or removes some for 3.1.
+type keywords struct {
+	common    []string
+	version31 []string
+	only30    []string
+}
+
+var objectKeys = map[string]keywords{
+	"document":       {common: []string{"openapi", "info", "servers", "paths", "components", "security", "tags", "externalDocs"}, version31: []string{"jsonSchemaDialect", "webhooks"}},
+	"info":           {common: []string{"title", "description", "termsOfService", "contact", "license", "version"}, version31: []string{"summary"}},
+	"tag":            {common: []string{"name", "description", "externalDocs"}},
+	"components":     {common: []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks"}, version31: []string{"pathItems"}},
+	"securityScheme": {common: []string{"type", "description", "name", "in", "scheme", "bearerFormat", "flows", "openIdConnectUrl"}},
+	"pathItem":       {common: []string{"$ref", "summary", "description", "servers", "parameters", "get", "put", "post", "delete", "options", "head", "patch", "trace"}},
+	"operation":      {common: []string{"tags", "summary", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "callbacks", "deprecated", "security", "servers"}},
+	"parameter":      {common: []string{"name", "in", "description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"}},
+	"header":         {common: []string{"description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"}},
+	"requestBody":    {common: []string{"description", "content", "required"}},
+	"mediaType":      {common: []string{"schema", "example", "examples", "encoding"}},
+	"response":       {common: []string{"description", "headers", "content", "links"}},
+	"schema": {
+		common: []string{"title", "multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength",
+			"pattern", "maxItems", "minItems", "uniqueItems", "maxProperties", "minProperties", "required", "enum", "type", "allOf",
+			"oneOf", "anyOf", "not", "items", "properties", "additionalProperties", "description", "format", "default", "discriminator",
+			"readOnly", "writeOnly", "xml", "externalDocs", "example", "deprecated", "$ref"},
+		version31: []string{"$schema", "$id", "$anchor", "$dynamicAnchor", "$dynamicRef", "$defs", "$comment", "const", "examples",
+			"prefixItems", "contains", "minContains", "maxContains", "patternProperties", "propertyNames", "dependentRequired",
+			"dependentSchemas", "unevaluatedItems", "unevaluatedProperties", "if", "then", "else", "contentEncoding",
+			"contentMediaType", "contentSchema"},
+		only30: []string{"nullable"},
+	},
+}
+
+// allowed reports whether key is a field of the object kind in the given version.
+func (k keywords) allowed(key string, v31 bool) bool {
+	for _, item := range k.common {
+		if item == key {
+			return true
+		}
+	}
+	var extra = k.only30
+	if v31 {
+		extra = k.version31
+	}
+	for _, item := range extra {
+		if item == key {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	v31                 bool
+	schemas             map[string]bool
+	schemes             map[string]bool
+	parameterComponents map[string]*yaml.Node
+	problems            []string
+}
+
+// CheckStructure adds the rules the official schemas leave to the specification text, such as
+// declared path parameters, unique parameters and resolvable $ref, and checks Schema Objects,
+// which the 3.1 schema does not validate. GenerateYaml runs it after ValidateSchema. It enforces
+// these rules, for the version the document declares:
+//   - openapi is 3.0.x or 3.1.x, info has a string title and version, paths exist in 3.0
+//   - objects only hold the fields their kind declares in that version, or x- extensions
+//   - component names match ^[a-zA-Z0-9.\-_]+$, security schemes have a known type and the
+//     fields that type requires, tags are unique and security requirements name declared schemes
+//   - paths begin with /, path items only hold known methods, every {param} of a path is declared
+//   - parameters have a name and a known location, are unique per location, are required in the
+//     path, have exactly one of schema or content and a style allowed for their location
+//   - request bodies have content, media types contain /, responses are keyed by status codes and
+//     have a description, headers have a schema or content
+//   - schemas have known types, arrays have items in 3.0, nullable only appears in 3.0 while type
+//     lists and the null type only appear in 3.1, exclusive bounds are booleans in 3.0 and numbers
+//     in 3.1, enums are non-empty lists
+//   - $ref to #/components/schemas/ point to a declared schema; path parameters may be declared
+//     by a $ref to #/components/parameters/
+func CheckStructure(doc *yaml.Node) error {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	var v = validator{schemas: map[string]bool{}, schemes: map[string]bool{}, parameterComponents: map[string]*yaml.Node{}}
+	if doc.Kind != yaml.MappingNode {
+		return &StructureError{Problems: []string{"document is not an object"}}
+	}
+
+	var version = value(doc, "openapi")
+	if version == nil || !versionPattern.MatchString(version.Value) {
+		v.fail("openapi", "version must be 3.0.x or 3.1.x")
+	} else {
+		v.v31 = strings.HasPrefix(version.Value, "3.1")
+	}
+	v.keys("document", "document", doc)
+	if info := value(doc, "info"); info == nil || info.Kind != yaml.MappingNode {
+		v.fail("info", "is required")
+	} else {
+		v.keys("info", "info", info)
+		for _, key := range []string{"title", "version"} {
+			if item := value(info, key); item == nil || item.Kind != yaml.ScalarNode || item.ShortTag() != "!!str" {
+				v.fail("info."+key, "must be a string")
+			}
+		}
+	}
+
+	if components := value(doc, "components"); components != nil {
+		eachPair(value(components, "parameters"), func(name string, param *yaml.Node) { v.parameterComponents[name] = param })
+		v.keys("components", "components", components)
+		v.components(components)
+	}
+	if tags := value(doc, "tags"); tags != nil {
+		var seen = map[string]bool{}
+		for i, tag := range tags.Content {
+			var location = fmt.Sprintf("tags[%d]", i)
+			v.keys(location, "tag", tag)
+			var name = value(tag, "name")
+			if name == nil || name.Value == "" {
+				v.fail(location, "name is required")
+			} else if seen[name.Value] {
+				v.fail(location, "duplicate tag "+name.Value)
+			} else {
+				seen[name.Value] = true
+			}
+		}
+	}
+	if security := value(doc, "security"); security != nil {
+		v.security("security", security)
+	}
+
+	var paths = value(doc, "paths")
+	if paths == nil && !v.v31 {
+		v.fail("paths", "is required")
+	}
+	if paths != nil {
+		v.paths(paths)
+	}
+
+	if len(v.problems) > 0 {
+		return &StructureError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) fail(path, problem string) {
+	v.problems = append(v.problems, path+": "+problem)
+}
+
+// keys reports the fields of an object which its kind does not declare in the document version.
+func (v *validator) keys(location, kind string, node *yaml.Node) {
+	var known = objectKeys[kind]
+	eachPair(node, func(key string, _ *yaml.Node) {
+		if strings.HasPrefix(key, "x-") || known.allowed(key, v.v31) {
+			return
+		}
+		if v.v31 && kind == "schema" && key == "nullable" {
+			v.fail(location, "nullable is replaced by a null type in OpenAPI 3.1")
+			return
+		}
+		v.fail(location, "unknown field "+key)
+	})
+}
+
+func (v *validator) components(components *yaml.Node) {
+	if schemas := value(components, "schemas"); schemas != nil {
+		eachPair(schemas, func(name string, _ *yaml.Node) {
+			v.schemas[name] = true
+		})
+		eachPair(schemas, func(name string, schema *yaml.Node) {
+			if !componentPattern.MatchString(name) {
+				v.fail("components.schemas."+name, "invalid component name")
+			}
+			v.schema("components.schemas."+name, schema)
+		})
+	}
+	if schemes := value(components, "securitySchemes"); schemes != nil {
+		eachPair(schemes, func(name string, scheme *yaml.Node) {
+			var path = "components.securitySchemes." + name
+			v.schemes[name] = true
+			if !componentPattern.MatchString(name) {
+				v.fail(path, "invalid component name")
+			}
+			if scheme.Kind != yaml.MappingNode {
+				v.fail(path, "must be an object")
+				return
+			}
+			if value(scheme, "$ref") != nil {
+				return
+			}
+			v.keys(path, "securityScheme", scheme)
+			var kind = scalar(scheme, "type")
+			if !schemeTypes[kind] || (kind == "mutualTLS" && !v.v31) {
+				v.fail(path, "invalid type "+kind)
+			}
+			var required = map[string][]string{
+				"apiKey":        {"name", "in"},
+				"http":          {"scheme"},
+				"oauth2":        {"flows"},
+				"openIdConnect": {"openIdConnectUrl"},
+			}
+			for _, key := range required[kind] {
+				if value(scheme, key) == nil {
+					v.fail(path, key+" is required for "+kind)
+				}
+			}
+			if kind == "apiKey" && !map[string]bool{"query": true, "header": true, "cookie": true}[scalar(scheme, "in")] {
+				v.fail(path, "in must be query, header or cookie")
+			}
+		})
+	}
+}
+
+func (v *validator) security(path string, requirements *yaml.Node) {
+	if requirements.Kind != yaml.SequenceNode {
+		v.fail(path, "must be a list of security requirements")
+		return
+	}
+	for i, requirement := range requirements.Content {
+		var item = fmt.Sprintf("%s[%d]", path, i)
+		if requirement.Kind != yaml.MappingNode {
+			v.fail(item, "must be an object")
+			continue
+		}
+		eachPair(requirement, func(name string, scopes *yaml.Node) {
+			if !v.schemes[name] {
+				v.fail(item, "undefined security scheme "+name)
+			}
+			if scopes.Kind != yaml.SequenceNode {
+				v.fail(item+"."+name, "scopes must be a list")
+			}
+		})
+	}
+}
+
+func (v *validator) paths(paths *yaml.Node) {
+	if paths.Kind != yaml.MappingNode {
+		v.fail("paths", "must be an object")
+		return
+	}
+	eachPair(paths, func(path string, item *yaml.Node) {
+		var location = "paths." + path
+		if !strings.HasPrefix(path, "/") {
+			v.fail(location, "path must begin with /")
+		}
+		if item.Kind != yaml.MappingNode {
+			v.fail(location, "must be an object")
+			return
+		}
+		v.keys(location, "pathItem", item)
+		var shared = value(item, "parameters")
+		if shared != nil {
+			v.parameters(location+".parameters", shared)
+		}
+		eachPair(item, func(key string, operation *yaml.Node) {
+			if methods[key] {
+				v.operation(location+"."+key, path, operation, shared)
+			}
+		})
+	})
+}
+
+func (v *validator) operation(location, path string, operation, shared *yaml.Node) {
+	if operation.Kind != yaml.MappingNode {
+		v.fail(location, "must be an object")
+		return
+	}
+	v.keys(location, "operation", operation)
+	var declared = map[string]bool{}
+	for _, list := range []*yaml.Node{shared, value(operation, "parameters")} {
+		if list == nil {
+			continue
+		}
+		for _, param := range list.Content {
+			if ref, ok := strings.CutPrefix(scalar(param, "$ref"), "#/components/parameters/"); ok && v.parameterComponents[ref] != nil {
+				param = v.parameterComponents[ref]
+			}
+			if scalar(param, "in") == "path" {
+				declared[scalar(param, "name")] = true
+			}
+		}
+	}
+	if params := value(operation, "parameters"); params != nil {
+		v.parameters(location+".parameters", params)
+	}
+	for _, match := range templatePattern.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			v.fail(location, "path parameter "+match[1]+" is not declared")
+		}
+	}
+	if body := value(operation, "requestBody"); body != nil {
+		if value(body, "$ref") == nil {
+			v.keys(location+".requestBody", "requestBody", body)
+			v.content(location+".requestBody", value(body, "content"), true)
+		} else {
+			v.ref(location+".requestBody", body)
+		}
+	}
+
+	var responses = value(operation, "responses")
+	if responses == nil || responses.Kind != yaml.MappingNode || len(responses.Content) == 0 {
+		if !v.v31 || responses != nil {
+			v.fail(location, "responses are required")
+		}
+	} else {
+		eachPair(responses, func(status string, response *yaml.Node) {
+			var item = location + ".responses." + status
+			if !statusPattern.MatchString(status) && !strings.HasPrefix(status, "x-") {
+				v.fail(item, "invalid status code")
+			}
+			if value(response, "$ref") != nil {
+				v.ref(item, response)
+				return
+			}
+			v.keys(item, "response", response)
+			if description := value(response, "description"); description == nil || description.ShortTag() != "!!str" {
+				v.fail(item, "description is required")
+			}
+			if content := value(response, "content"); content != nil {
+				v.content(item, content, false)
+			}
+			if headers := value(response, "headers"); headers != nil {
+				eachPair(headers, func(name string, header *yaml.Node) {
+					var location = item + ".headers." + name
+					if value(header, "$ref") != nil {
+						v.ref(location, header)
+						return
+					}
+					v.keys(location, "header", header)
+					if value(header, "schema") == nil && value(header, "content") == nil {
+						v.fail(location, "schema or content is required")
+					} else if schema := value(header, "schema"); schema != nil {
+						v.schema(location, schema)
+					}
+					if style := scalar(header, "style"); style != "" && !styles["header"][style] {
+						v.fail(location, "style "+style+" is not allowed for headers")
+					}
+				})
+			}
+		})
+	}
+	if security := value(operation, "security"); security != nil {
+		v.security(location+".security", security)
+	}
+}
+
+func (v *validator) parameters(location string, params *yaml.Node) {
+	if params.Kind != yaml.SequenceNode {
+		v.fail(location, "must be a list")
+		return
+	}
+	var seen = map[string]bool{}
+	for i, param := range params.Content {
+		var item = fmt.Sprintf("%s[%d]", location, i)
+		if value(param, "$ref") != nil {
+			v.ref(item, param)
+			continue
+		}
+		v.keys(item, "parameter", param)
+		var name, in = scalar(param, "name"), scalar(param, "in")
+		if name == "" {
+			v.fail(item, "name is required")
+		}
+		if !locations[in] {
+			v.fail(item, "in must be query, header, path or cookie")
+		}
+		if seen[in+":"+name] {
+			v.fail(item, "duplicate parameter "+name+" in "+in)
+		}
+		seen[in+":"+name] = true
+		if in == "path" && scalar(param, "required") != "true" {
+			v.fail(item, "path parameters must be required")
+		}
+		if style := scalar(param, "style"); style != "" && locations[in] && !styles[in][style] {
+			v.fail(item, "style "+style+" is not allowed in "+in)
+		}
+		if value(param, "allowReserved") != nil && in != "query" {
+			v.fail(item, "allowReserved only applies to query parameters")
+		}
+		if value(param, "allowEmptyValue") != nil && in != "query" {
+			v.fail(item, "allowEmptyValue only applies to query parameters")
+		}
+		for _, key := range []string{"required", "deprecated", "explode", "allowReserved", "allowEmptyValue"} {
+			if flag := value(param, key); flag != nil && flag.ShortTag() != "!!bool" {
+				v.fail(item, key+" must be a boolean")
+			}
+		}
+		var schema, content = value(param, "schema"), value(param, "content")
+		if (schema == nil) == (content == nil) {
+			v.fail(item, "exactly one of schema or content is required")
+		}
+		if schema != nil {
+			v.schema(item+".schema", schema)
+		}
+		if content != nil {
+			v.content(item, content, true)
+		}
+	}
+}
+
+func (v *validator) content(location string, content *yaml.Node, required bool) {
+	if content == nil || content.Kind != yaml.MappingNode || len(content.Content) == 0 {
+		if required {
+			v.fail(location, "content is required")
+		}
+		return
+	}
+	eachPair(content, func(mediaType string, media *yaml.Node) {
+		if !strings.Contains(mediaType, "/") {
+			v.fail(location+".content", "invalid media type "+mediaType)
+		}
+		v.keys(location+".content."+mediaType, "mediaType", media)
+		if schema := value(media, "schema"); schema != nil {
+			v.schema(location+".content."+mediaType+".schema", schema)
+		}
+	})
+}
+
+func (v *validator) ref(location string, node *yaml.Node) {
+	var ref = scalar(node, "$ref")
+	if name, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok && !v.schemas[name] {
+		v.fail(location, "undefined schema "+ref)
+	}
+}
+
+func (v *validator) schema(location string, schema *yaml.Node) {
+	if schema.Kind != yaml.MappingNode {
+		v.fail(location, "schema must be an object")
+		return
+	}
+	if value(schema, "$ref") != nil {
+		v.ref(location, schema)
+		if !v.v31 {
+			// siblings of $ref are ignored in 3.0
+			return
+		}
+	}
+	v.keys(location, "schema", schema)
+	var kind = value(schema, "type")
+	switch {
+	case kind == nil:
+	case kind.Kind == yaml.ScalarNode:
+		if !schemaTypes[kind.Value] && !(v.v31 && kind.Value == "null") {
+			v.fail(location, "invalid type "+kind.Value)
+		}
+	case kind.Kind == yaml.SequenceNode && v.v31:
+		for _, item := range kind.Content {
+			if !schemaTypes[item.Value] && item.Value != "null" {
+				v.fail(location, "invalid type "+item.Value)
+			}
+		}
+	default:
+		v.fail(location, "type must be a string in OpenAPI 3.0")
+	}
+	if kind != nil && kind.Value == "array" && value(schema, "items") == nil && !v.v31 {
+		v.fail(location, "items are required for arrays")
+	}
+	for _, key := range []string{"exclusiveMinimum", "exclusiveMaximum"} {
+		if item := value(schema, key); item != nil {
+			// booleans in 3.0, the bound itself in 3.1
+			if isBool := item.ShortTag() == "!!bool"; isBool == v.v31 {
+				v.fail(location, key+" must be a "+map[bool]string{true: "number", false: "boolean"}[v.v31])
+			}
+		}
+	}
+	if enum := value(schema, "enum"); enum != nil && (enum.Kind != yaml.SequenceNode || len(enum.Content) == 0) {
+		v.fail(location, "enum must be a non-empty list")
+	}
+	if required := value(schema, "required"); required != nil {
+		if required.Kind != yaml.SequenceNode {
+			v.fail(location, "required must be a list")
+		}
+	}
+	if properties := value(schema, "properties"); properties != nil {
+		eachPair(properties, func(name string, property *yaml.Node) {
+			v.schema(location+".properties."+name, property)
+		})
+	}
+	for _, key := range []string{"items", "not"} {
+		if item := value(schema, key); item != nil {
+			v.schema(location+"."+key, item)
+		}
+	}
+	if item := value(schema, "additionalProperties"); item != nil && item.Kind == yaml.MappingNode {
+		v.schema(location+".additionalProperties", item)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list := value(schema, key); list != nil {
+			for i, item := range list.Content {
+				v.schema(fmt.Sprintf("%s.%s[%d]", location, key, i), item)
+			}
+		}
+	}
+}
+
+// walkSchemas calls fn for every schema of a marshaled document, parents before children.
+func walkSchemas(doc *yaml.Node, fn func(schema *yaml.Node)) {
+	var visit func(schema *yaml.Node)
+	visit = func(schema *yaml.Node) {
+		if schema == nil || schema.Kind != yaml.MappingNode {
+			return
+		}
+		fn(schema)
+		if properties := value(schema, "properties"); properties != nil {
+			eachPair(properties, func(_ string, property *yaml.Node) { visit(property) })
+		}
+		for _, key := range []string{"items", "not", "additionalProperties"} {
+			visit(value(schema, key))
+		}
+		for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+			if list := value(schema, key); list != nil {
+				for _, item := range list.Content {
+					visit(item)
+				}
+			}
+		}
+	}
+	var content = func(node *yaml.Node) {
+		if node == nil {
+			return
+		}
+		eachPair(node, func(_ string, media *yaml.Node) { visit(value(media, "schema")) })
+	}
+	if components := value(doc, "components"); components != nil {
+		if schemas := value(components, "schemas"); schemas != nil {
+			eachPair(schemas, func(_ string, schema *yaml.Node) { visit(schema) })
+		}
+		// components kept from a hand-written document
+		for _, key := range []string{"parameters", "headers"} {
+			eachPair(value(components, key), func(_ string, item *yaml.Node) { visit(value(item, "schema")) })
+		}
+		for _, key := range []string{"requestBodies", "responses"} {
+			eachPair(value(components, key), func(_ string, item *yaml.Node) { content(value(item, "content")) })
+		}
+	}
+	if paths := value(doc, "paths"); paths != nil {
+		eachPair(paths, func(_ string, item *yaml.Node) {
+			eachPair(item, func(_ string, operation *yaml.Node) {
+				if params := value(operation, "parameters"); params != nil {
+					for _, param := range params.Content {
+						visit(value(param, "schema"))
+					}
+				}
+				if body := value(operation, "requestBody"); body != nil {
+					content(value(body, "content"))
+				}
+				if responses := value(operation, "responses"); responses != nil {
+					eachPair(responses, func(_ string, response *yaml.Node) {
+						content(value(response, "content"))
+						if headers := value(response, "headers"); headers != nil {
+							eachPair(headers, func(_ string, header *yaml.Node) { visit(value(header, "schema")) })
+						}
+					})
+				}
+			})
+		})
+	}
+}
+
+// upgradeSchema rewrites the 3.0 keywords of a schema for 3.1: nullable becomes a null type and
+// boolean exclusive bounds become numbers.
+func upgradeSchema(schema *yaml.Node) {
+	if nullable := value(schema, "nullable"); nullable != nil {
+		removeKey(schema, "nullable")
+		if nullable.Value == "true" {
+			if kind := value(schema, "type"); kind != nil && kind.Kind == yaml.ScalarNode {
+				*kind = yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle, Content: []*yaml.Node{stringNode(kind.Value), stringNode("null")}}
+			} else if allOf := value(schema, "allOf"); allOf != nil && len(allOf.Content) == 1 {
+				allOf.Content = append(allOf.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: "type"}, stringNode("null"),
+				}})
+				for i := 0; i+1 < len(schema.Content); i += 2 {
+					if schema.Content[i].Value == "allOf" {
+						schema.Content[i].Value = "anyOf"
+					}
+				}
+			}
+		}
+	}
+	for _, bound := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		if exclusive := value(schema, bound[0]); exclusive != nil && exclusive.ShortTag() == "!!bool" {
+			var limit = value(schema, bound[1])
+			removeKey(schema, bound[0])
+			if exclusive.Value == "true" && limit != nil {
+				removeKey(schema, bound[1])
+				appendPair(schema, bound[0], limit)
+			}
+		}
+	}
+}
+
+// value returns the value of a key of a mapping node.
+func value(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalar returns the value of a scalar key of a mapping node, or an empty string.
+func scalar(node *yaml.Node, key string) string {
+	if item := value(node, key); item != nil && item.Kind == yaml.ScalarNode {
+		return item.Value
+	}
+	return ""
+}
+
+func eachPair(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i].Value, node.Content[i+1])
+	}
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
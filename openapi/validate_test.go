@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const minimalDocument = `
+openapi: %s
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          %s
+`
+
+func checkDocument(t *testing.T, source string) error {
+	t.Helper()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &node); err != nil {
+		t.Fatal(err)
+	}
+	return CheckStructure(&node)
+}
+
+func TestCheckStructure(t *testing.T) {
+	var tests = []struct {
+		name    string
+		version string
+		schema  string
+		problem string
+	}{
+		{"valid 3.0", "3.0.3", "{type: string, nullable: true}", ""},
+		{"valid 3.1", "3.1.0", `{type: [string, "null"], const: a}`, ""},
+		{"nullable in 3.1", "3.1.0", "{type: string, nullable: true}", "nullable is replaced by a null type"},
+		{"type list in 3.0", "3.0.3", `{type: [string, "null"]}`, "type must be a string"},
+		{"3.1 keyword in 3.0", "3.0.3", "{type: string, const: a}", "unknown field const"},
+		{"misspelled keyword", "3.0.3", "{type: string, maxLenght: 3}", "unknown field maxLenght"},
+		{"numeric bound in 3.0", "3.0.3", "{type: integer, minimum: 1, exclusiveMinimum: 1}", "exclusiveMinimum must be a boolean"},
+		{"boolean bound in 3.1", "3.1.0", "{type: integer, minimum: 1, exclusiveMinimum: true}", "exclusiveMinimum must be a number"},
+		{"array without items", "3.0.3", "{type: array}", "items are required"},
+		{"unknown type", "3.0.3", "{type: text}", "invalid type text"},
+		{"undefined ref", "3.0.3", "{$ref: '#/components/schemas/Missing'}", "undefined schema"},
+		{"extension", "3.0.3", "{type: string, x-order: 1}", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err = checkDocument(t, fmt.Sprintf(minimalDocument, test.version, test.schema))
+			if test.problem == "" {
+				if err != nil {
+					t.Fatalf("unexpected problems: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Fatalf("got %v, want a problem containing %q", err, test.problem)
+			}
+		})
+	}
+}
+
+func TestCheckStructureOperations(t *testing.T) {
+	var document = func(parameters, extra string) string {
+		return `
+openapi: 3.0.3
+info: {title: API, version: 1.0.0}
+paths:
+  /users/{id}:
+    get:
+      ` + extra + `
+      parameters:
+` + parameters + `
+      responses:
+        "200": {description: OK}
+`
+	}
+	var id = "        - {name: id, in: path, required: true, schema: {type: integer}}\n"
+	var tests = []struct {
+		name       string
+		parameters string
+		extra      string
+		problem    string
+	}{
+		{"valid", id + "        - {name: filter, in: query, style: deepObject, explode: true, schema: {type: object}}", "", ""},
+		{"undeclared path parameter", "        - {name: q, in: query, schema: {type: string}}", "", "path parameter id is not declared"},
+		{"duplicate parameter", id + "        - {name: order, in: query, schema: {type: string}}\n        - {name: order, in: query, schema: {type: string}}", "", "duplicate parameter order in query"},
+		{"optional path parameter", "        - {name: id, in: path, schema: {type: integer}}", "", "path parameters must be required"},
+		{"style of another location", id + "        - {name: q, in: query, style: matrix, schema: {type: string}}", "", "style matrix is not allowed in query"},
+		{"deepObject in path", "        - {name: id, in: path, required: true, style: deepObject, schema: {type: integer}}", "", "style deepObject is not allowed in path"},
+		{"allowReserved outside query", id + "        - {name: X-Key, in: header, allowReserved: true, schema: {type: string}}", "", "allowReserved only applies to query"},
+		{"schema and content", id + "        - {name: q, in: query, schema: {type: string}, content: {application/json: {}}}", "", "exactly one of schema or content"},
+		{"unknown parameter field", id + "        - {name: q, in: query, requried: true, schema: {type: string}}", "", "unknown field requried"},
+		{"unknown operation field", id, "sumary: typo", "unknown field sumary"},
+		{"undefined security scheme", id, "security: [{ApiKey: []}]", "undefined security scheme ApiKey"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err = checkDocument(t, document(test.parameters, test.extra))
+			if test.problem == "" {
+				if err != nil {
+					t.Fatalf("unexpected problems: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Fatalf("got %v, want a problem containing %q", err, test.problem)
+			}
+		})
+	}
+}
+
+func TestCheckStructureDocument(t *testing.T) {
+	var tests = []struct {
+		name    string
+		source  string
+		problem string
+	}{
+		{"missing version", "info: {title: API, version: '1'}\npaths: {}", "version must be 3.0.x or 3.1.x"},
+		{"missing paths in 3.0", "openapi: 3.0.3\ninfo: {title: API, version: '1'}", "paths: is required"},
+		{"no paths in 3.1", "openapi: 3.1.0\ninfo: {title: API, version: '1'}", ""},
+		{"numeric info version", "openapi: 3.0.3\ninfo: {title: API, version: 1}\npaths: {}", "info.version: must be a string"},
+		{"unknown root field", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {}\nwebhooks: {}", "unknown field webhooks"},
+		{"path without slash", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {users: {}}", "path must begin with /"},
+		{"unknown method", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {/users: {fetch: {}}}", "unknown field fetch"},
+		{"duplicate tag", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {}\ntags: [{name: a}, {name: a}]", "duplicate tag a"},
+		{"apiKey without name", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {}\ncomponents: {securitySchemes: {Key: {type: apiKey, in: header}}}", "name is required for apiKey"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err = checkDocument(t, test.source)
+			if test.problem == "" {
+				if err != nil {
+					t.Fatalf("unexpected problems: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Fatalf("got %v, want a problem containing %q", err, test.problem)
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	var tests = []struct {
+		name    string
+		source  string
+		problem string
+	}{
+		{"valid 3.0", fmt.Sprintf(minimalDocument, "3.0.3", "{type: string}"), ""},
+		{"valid 3.1", fmt.Sprintf(minimalDocument, "3.1.0", "{type: string}"), ""},
+		{"missing info version", "openapi: 3.0.3\ninfo: {title: API}\npaths: {}", "missing property 'version'"},
+		{"response without description", "openapi: 3.1.0\ninfo: {title: API, version: '1'}\npaths: {/a: {get: {responses: {'200': {}}}}}", "missing property 'description'"},
+		{"unknown location", "openapi: 3.0.3\ninfo: {title: API, version: '1'}\npaths: {/a: {get: {parameters: [{name: q, in: body, schema: {type: string}}], responses: {'200': {description: OK}}}}}", "value must be 'query'"},
+		{"3.1 without paths", "openapi: 3.1.0\ninfo: {title: API, version: '1'}", "missing property"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(test.source), &node); err != nil {
+				t.Fatal(err)
+			}
+			var err = ValidateSchema(&node)
+			if test.problem == "" {
+				if err != nil {
+					t.Fatalf("unexpected problems: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Fatalf("got %v, want a problem containing %q", err, test.problem)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package postman
 import (
 	"fmt"
 	"github.com/getevo/docify/serializer"
-	"github.com/getevo/evo/v2/lib/gpath"
 	"github.com/getevo/evo/v2/lib/log"
 	"github.com/getevo/restify"
 	"reflect"
@@ -11,27 +10,38 @@ import (
 	"strings"
 )
 
-func Generate(project *serializer.Doc) {
-	var collections []Collection
+// Generator writes the restify Postman collection.
+type Generator struct{}
 
+func (Generator) Name() string {
+	return "postman"
+}
+
+// Generate writes the restify Postman collection to the output directory.
+func Generate(project *serializer.Doc, opts serializer.Options) error {
+	return Generator{}.Generate(project, serializer.NewFileWriter(opts, project.Settings.GeneratorOptions["postman"]))
+}
+
+func (Generator) Generate(project *serializer.Doc, w serializer.Writer) error {
 	var collection = NewCollection(project.Title+" Restify", project.Description)
 
 	for _, entity := range project.Entities {
 		log.Info("Postman Entity: " + entity.Name)
 		folder := collection.CreateFolder(entity.Pkg+"."+entity.Name, entity.Name+" API List")
 
-		for _, action := range entity.Endpoints {
+		for _, op := range entity.Operations {
+			var action = op.Endpoint
 			req := Request{
 				Url: &Url{
-					Raw: "{{ restify_base }}" + action.AbsoluteURI,
+					Raw: "{{ restify_base }}" + op.Path,
 					Host: []string{
 						"{{ restify_base }}",
 					},
 					Path: []string{
-						action.AbsoluteURI,
+						op.Path,
 					},
 				},
-				Method:      string(action.Method),
+				Method:      op.Method,
 				Description: GenerateDescription(entity, action),
 				Body: &Body{
 					Mode: BodyModeRaw,
@@ -41,40 +51,22 @@ func Generate(project *serializer.Doc) {
 
 			req.Body.SetLanguage("json")
 
-			if action.AcceptData {
-
-				if action.Batch {
-					req.Body.Raw = entity.DataSample.BatchJSON
+			if op.Body != nil {
+				if op.Body.Batch {
+					req.Body.Raw = entity.DataSample.Batch().JSON()
+				} else if op.Body.Variant == serializer.BodyCreate {
+					req.Body.Raw = entity.DataSample.Create.JSON()
 				} else {
-					if action.Method == "PUT" {
-						req.Body.Raw = entity.DataSample.CreateJSON
-					} else {
-						req.Body.Raw = entity.DataSample.UpdateJSON
-					}
-
+					req.Body.Raw = entity.DataSample.Update.JSON()
 				}
-
 			}
-			if action.Pagination {
-				req.Url.AddQuery("page", ":page", "specify page to load (optional)", true)
-				req.Url.AddQuery("size", ":size", "specify size of results (optional, default 10, max 100)", true)
-			}
-			if len(entity.Association) > 0 && action.Method == "GET" {
-				var associations = ""
-				for _, association := range entity.Association {
-					associations += fmt.Sprintf("%s,", association.Name)
-				}
-				associations = strings.TrimSuffix(associations, ",")
-				req.Url.AddQuery("associations", "", fmt.Sprintf("Accepts \"%s\" as loadable association(s) (optional, accept comma seperated values)", associations), true)
-			}
-			if action.Method == "GET" {
-				req.Url.AddQuery("sort", "field.desc", "sort results by field. field.asc or field.desc (optional, accept comma seperated values)", true)
-				req.Url.AddQuery("filters", "field[op]=value", "filter results by field value. (optional)", true)
-				for _, item := range entity.Fields {
-					req.Url.AddQuery(item.DBName, item.DBName+"[eq]=:value", fmt.Sprintf("filter results by %s (optional, possible operators: eq, neq, gt, gte, lt, lte, in, notin, between, contains, search, isnull, notnull)", item.Name), true)
+			for _, param := range op.QueryParams {
+				var key, value = param.Name, param.Example
+				if param.Style == "deepObject" {
+					key, value = param.Name+"[eq]", ":value"
 				}
+				req.Url.AddQuery(key, value, param.Description+" (optional)", true)
 			}
-			req.Url.AddQuery("debug", "debug=restify", "enable debug mode (optional, default false)", true)
 
 			folder.AppendItem(Item{
 				Name:        action.Name,
@@ -85,21 +77,11 @@ func Generate(project *serializer.Doc) {
 
 	}
 
-	if gpath.IsFileExist("./docify/restify.json") {
-		err := gpath.Remove("./docify/restify.json")
-		if err != nil {
-			log.Error("Error writing to file:", err)
-		}
-	}
-	var b, _ = collection.ToJson()
-	err := gpath.Write("./docify/restify.json", b)
-
+	var b, err = collection.ToJson()
 	if err != nil {
-		log.Error("Error writing to file:", err)
+		return err
 	}
-
-	collections = append(collections, *collection)
-
+	return w.Write("restify.json", b)
 }
 
 func GenerateDescription(entity serializer.Entity, action *restify.Endpoint) string {
@@ -137,17 +119,24 @@ func GenerateDescription(entity serializer.Entity, action *restify.Endpoint) str
 		description = append(description, "### Acceptable fields and their types:")
 		description = append(description, "| Field | Type | Description | Validation |")
 		description = append(description, "| ------ | ------ | ------ | ------ |")
+		var fields = map[string]serializer.Field{}
+		for _, field := range entity.Fields {
+			fields[field.Name] = field
+		}
 		for _, field := range action.Resource.Schema.Fields {
-			var jsonField = strings.Split(field.Tag.Get("json"), ",")[0]
-			if field.Tag.Get("json") == "-" || strings.Contains(field.Tag.Get("json"), "omit_decode") {
-				jsonField = field.Name
-			}
-
 			if strings.TrimSpace(string(field.GORMDataType)) == "" {
 				continue
 			}
+			item, ok := fields[field.Name]
+			if !ok || !item.InRequest() {
+				continue
+			}
+			var jsonField = item.JsonTag
 
 			var additional []string
+			if v := item.Description; v != "" {
+				additional = append(additional, strings.ReplaceAll(v, "\n", "<br>"))
+			}
 			if t, ok := field.TagSettings["TYPE"]; ok && strings.HasPrefix(t, "enum") {
 				additional = append(additional, "`Type:"+t)
 			}
@@ -175,17 +164,19 @@ func GenerateDescription(entity serializer.Entity, action *restify.Endpoint) str
 			if field.Scale > 0 {
 				additional = append(additional, "`Scale:"+strconv.Itoa(field.Scale)+"`")
 			}
-			if !field.Updatable {
+			if !item.InUpdate() {
 				additional = append(additional, "`Cannot be updated`")
 			}
-			if !field.Creatable {
+			if !item.InCreate() {
 				additional = append(additional, "`Cannot be created`")
 			}
-			if !field.Readable {
+			if !item.InResponse() {
 				additional = append(additional, "`Unreadable`")
 			}
 			var validation = "`none`"
-			if field.Tag.Get("validation") != "" {
+			if v := item.Rules; len(v) > 0 {
+				validation = v.Text()
+			} else if field.Tag.Get("validation") != "" {
 				validation = field.Tag.Get("validation")
 			}
 
@@ -196,22 +187,18 @@ func GenerateDescription(entity serializer.Entity, action *restify.Endpoint) str
 
 	if action.Filterable {
 		var associations []string
-		for _, field := range action.Resource.Schema.Fields {
-			if strings.TrimSpace(string(field.GORMDataType)) == "" {
-
-				if field.FieldType.Kind() == reflect.Slice {
-					associations = append(associations, fmt.Sprintf("| %s | `%s` | %s |", field.Name, "has many", "associations="+field.Name))
-				} else {
-					associations = append(associations, fmt.Sprintf("| %s | `%s` | %s |", field.Name, "belongs to", "associations="+field.Name))
-				}
-
+		for _, association := range entity.Association {
+			var target = association.EntityName
+			if association.JoinTable != "" {
+				target += " (join table `" + association.JoinTable + "`)"
 			}
+			associations = append(associations, fmt.Sprintf("| %s | `%s` | %s | %s |", association.Name, association.Relation(), target, "associations="+association.Name))
 		}
 		if len(associations) > 0 {
 			description = append(description, "---")
 			description = append(description, "### Loadable Associations:")
-			description = append(description, "| Association | Type | URL Pattern |")
-			description = append(description, "| ------ | ------ | ------ |")
+			description = append(description, "| Association | Type | Entity | URL Pattern |")
+			description = append(description, "| ------ | ------ | ------ | ------ |")
 			description = append(description, associations...)
 			description = append(description, "\n\nmore information: [Query Parameters Explanation](https://github.com/getevo/restify/blob/master/docs/endpoints.md#query-parameters-explanation)")
 		}
@@ -0,0 +1,93 @@
+package docify
+
+import (
+	"github.com/getevo/docify/serializer"
+	"reflect"
+	"strings"
+)
+
+// linkForeignKeys fills the foreign key of every column referenced by an association key and
+// resolves the referenced entity of all foreign keys.
+func linkForeignKeys(entities []serializer.Entity, tables map[string]*serializer.Entity) {
+	for idx := range entities {
+		for _, association := range entities[idx].Association {
+			for _, key := range association.Keys {
+				if key.References == "" {
+					continue
+				}
+				ownerTable, column := splitColumn(key.ForeignKey)
+				targetTable, reference := splitColumn(key.References)
+				var owner, target = tables[ownerTable], tables[targetTable]
+				if owner == nil || target == nil {
+					continue
+				}
+				for i := range owner.Fields {
+					if owner.Fields[i].DBName == column && owner.Fields[i].ForeignKey == nil {
+						owner.Fields[i].ForeignKey = &serializer.ForeignKey{
+							Table: targetTable,
+							Field: reference,
+						}
+					}
+				}
+			}
+		}
+	}
+	for idx := range entities {
+		for i := range entities[idx].Fields {
+			if fk := entities[idx].Fields[i].ForeignKey; fk != nil {
+				fk.Entity = tables[fk.Table]
+			}
+		}
+	}
+}
+
+func splitColumn(column string) (string, string) {
+	if i := strings.LastIndex(column, "."); i > -1 {
+		return column[:i], column[i+1:]
+	}
+	return "", column
+}
+
+// sampleOrder returns the entities so that every entity comes after the entities its foreign keys
+// reference. Cycles are broken in declaration order.
+func sampleOrder(entities []serializer.Entity) []*serializer.Entity {
+	var order []*serializer.Entity
+	var visited = map[*serializer.Entity]bool{}
+	var visit func(entity *serializer.Entity)
+	visit = func(entity *serializer.Entity) {
+		if visited[entity] {
+			return
+		}
+		visited[entity] = true
+		for _, field := range entity.Fields {
+			if field.ForeignKey != nil && field.ForeignKey.Entity != nil {
+				visit(field.ForeignKey.Entity)
+			}
+		}
+		order = append(order, entity)
+	}
+	for idx := range entities {
+		visit(&entities[idx])
+	}
+	return order
+}
+
+// referencedSample returns the sample value of the column a foreign key points to, if the
+// referenced entity has already been sampled.
+func referencedSample(entity *serializer.Entity, field serializer.Field) (interface{}, bool) {
+	var fk = field.ForeignKey
+	if fk == nil || fk.Entity == nil || fk.Entity == entity {
+		return nil, false
+	}
+	for _, item := range fk.Entity.Fields {
+		if item.DBName != fk.Field || item.SampleData == nil {
+			continue
+		}
+		var value = reflect.Indirect(reflect.ValueOf(item.SampleData))
+		if !value.IsValid() {
+			return nil, false
+		}
+		return value.Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,157 @@
+package docify
+
+import (
+	"github.com/getevo/docify/serializer"
+	"github.com/getevo/evo/v2/lib/log"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sensitiveRule is a SensitiveRule with its pattern compiled.
+type sensitiveRule struct {
+	pattern *regexp.Regexp
+	action  serializer.Sensitivity
+}
+
+// defaultSensitive protects secrets and common personal data when no project rule matches.
+// Names are matched word by word, so token matches access_token but not tokenizer, and only a
+// postal address is masked, not ip_address.
+var defaultSensitive = []sensitiveRule{
+	{
+		pattern: regexp.MustCompile(`(?i)(^|_)(password|passwd|secret|token|api_?key|private_?key|hash|salt|otp|pin|pin_?code|ssn|credit_?card|card_?number|cvv|iban)($|_)`),
+		action:  serializer.SensitivityFake,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(^|_)(e_?mail|phone|mobile|birth|birthday|dob|national_?id|passport|last_?name|surname)($|_)|(^|_)(street|home|postal|mailing|billing|shipping)_address($|_)|^address($|_)`),
+		action:  serializer.SensitivityMask,
+	},
+}
+
+// compileSensitive compiles the project rules once per settings and appends the built-in
+// rules. A rule without action masks; the actions none and public publish values unchanged.
+func compileSensitive(settings serializer.Settings) []sensitiveRule {
+	var rules []sensitiveRule
+	for _, rule := range settings.Sensitive {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			log.Error("invalid sensitive pattern ", rule.Pattern, ": ", err)
+			continue
+		}
+		var action = rule.Action
+		switch strings.ToLower(strings.TrimSpace(string(action))) {
+		case "":
+			action = serializer.SensitivityMask
+		case "none", "public":
+			action = serializer.SensitivityNone
+		}
+		rules = append(rules, sensitiveRule{pattern: pattern, action: action})
+	}
+	return append(rules, defaultSensitive...)
+}
+
+// getSensitivity resolves how a field is protected. The docify tag wins over project.yml rules,
+// which win over the built-in rules: `docify:"sensitive"` replaces the value with generated data,
+// `docify:"mask"` masks it and `docify:"public"` publishes it as is.
+func getSensitivity(tag reflect.StructTag, field serializer.Field, rules []sensitiveRule) serializer.Sensitivity {
+	for _, option := range strings.Split(tag.Get("docify"), ",") {
+		switch strings.TrimSpace(option) {
+		case "sensitive", "fake":
+			return serializer.SensitivityFake
+		case "mask":
+			return serializer.SensitivityMask
+		case "public":
+			return serializer.SensitivityNone
+		}
+	}
+	var names = []string{field.DBName, field.JsonTag, snakeCase(field.JsonTag)}
+	for _, rule := range rules {
+		for _, name := range names {
+			if name != "" && rule.pattern.MatchString(name) {
+				return rule.action
+			}
+		}
+	}
+	return serializer.SensitivityNone
+}
+
+// snakeCase separates the words of a camelCase or kebab-case name with underscores,
+// e.g. accessToken and APIKey become access_token and api_key.
+func snakeCase(name string) string {
+	var runes = []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '-' {
+			b.WriteByte('_')
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			var prev = runes[i-1]
+			var nextLower = i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// sampleFromDB reports whether a sample of the entity may be read from the database.
+func sampleFromDB(entity *serializer.Entity, settings serializer.Settings) bool {
+	if settings.DisableDBSamples {
+		return false
+	}
+	for _, name := range settings.SkipDBSamples {
+		if name == entity.Name || name == entity.ID || (entity.Resource != nil && name == entity.Resource.Table) {
+			return false
+		}
+	}
+	return true
+}
+
+// protectSample masks or replaces the sensitive fields of a row read from the database.
+func protectSample(object reflect.Value, entity *serializer.Entity, seed int64) {
+	for _, item := range entity.Fields {
+		var field = object.FieldByName(item.Name)
+		if item.Sensitive == serializer.SensitivityNone || !field.IsValid() || !field.CanSet() {
+			continue
+		}
+		var target = field
+		for target.Kind() == reflect.Ptr && !target.IsNil() {
+			target = target.Elem()
+		}
+		if item.Sensitive == serializer.SensitivityMask && target.Kind() == reflect.String {
+			target.SetString(maskString(target.String()))
+			continue
+		}
+		field.Set(reflect.Zero(field.Type()))
+		fakeField(newFaker(seed, entity.ID, item.Name), field, item)
+	}
+}
+
+// maskString keeps the first and last characters of a value, and the domain suffix of an email.
+func maskString(s string) string {
+	if s == "" {
+		return s
+	}
+	if i := strings.LastIndex(s, "@"); i > 0 {
+		var domain = s[i+1:]
+		if j := strings.LastIndex(domain, "."); j > -1 {
+			domain = maskString(domain[:j]) + domain[j:]
+		} else {
+			domain = maskString(domain)
+		}
+		return maskString(s[:i]) + "@" + domain
+	}
+	var runes = []rune(s)
+	switch {
+	case len(runes) <= 2:
+		return strings.Repeat("*", len(runes))
+	case len(runes) <= 4:
+		return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	}
+	return string(runes[0]) + strings.Repeat("*", utf8.RuneCountInString(s)-2) + string(runes[len(runes)-1])
+}
@@ -0,0 +1,46 @@
+package docify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getevo/docify/serializer"
+)
+
+func TestGetSensitivity(t *testing.T) {
+	var settings = serializer.Settings{Sensitive: []serializer.SensitiveRule{
+		{Pattern: `^email$`, Action: "public"},
+		{Pattern: `^nickname$`},
+		{Pattern: `^serial$`, Action: "none"},
+		{Pattern: `(`, Action: serializer.SensitivityFake},
+	}}
+	var rules = compileSensitive(settings)
+	var tests = []struct {
+		name string
+		tag  reflect.StructTag
+		want serializer.Sensitivity
+	}{
+		{"password", "", serializer.SensitivityFake},
+		{"access_token", "", serializer.SensitivityFake},
+		{"accessToken", "", serializer.SensitivityFake},
+		{"APIKey", "", serializer.SensitivityFake},
+		{"tokenizer", "", serializer.SensitivityNone},
+		{"hashtag", "", serializer.SensitivityNone},
+		{"spinner", "", serializer.SensitivityNone},
+		{"ip_address", "", serializer.SensitivityNone},
+		{"address", "", serializer.SensitivityMask},
+		{"billing_address", "", serializer.SensitivityMask},
+		{"date_of_birth", "", serializer.SensitivityMask},
+		{"email", "", serializer.SensitivityNone},
+		{"nickname", "", serializer.SensitivityMask},
+		{"serial", "", serializer.SensitivityNone},
+		{"phone", `docify:"public"`, serializer.SensitivityNone},
+		{"title", `docify:"mask"`, serializer.SensitivityMask},
+	}
+	for _, test := range tests {
+		var field = serializer.Field{JsonTag: test.name}
+		if got := getSensitivity(test.tag, field, rules); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
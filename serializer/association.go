@@ -0,0 +1,108 @@
+package serializer
+
+import (
+	"gorm.io/gorm/schema"
+	"path"
+	"reflect"
+)
+
+type RelationKind string
+
+const (
+	BelongsTo RelationKind = "belongs-to"
+	HasOne    RelationKind = "has-one"
+	HasMany   RelationKind = "has-many"
+	Many2Many RelationKind = "many2many"
+)
+
+// NewAssociation describes an association field of s, resolving the element type of slices
+// and taking the relationship kind, keys and join table from the gorm schema.
+// It returns false if the field does not reference a struct.
+func NewAssociation(field *schema.Field, s *schema.Schema) (Association, bool) {
+	var t = field.FieldType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var array = false
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		array = true
+		t = t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return Association{}, false
+	}
+
+	var association = Association{
+		Name:       field.Name,
+		JsonTag:    field.Name,
+		EntityName: path.Base(t.PkgPath()) + "." + t.Name(),
+		Array:      array,
+		Kind:       BelongsTo,
+	}
+	if array {
+		association.Kind = HasMany
+	}
+
+	rel, ok := s.Relationships.Relations[field.Name]
+	if !ok {
+		return association, true
+	}
+	switch rel.Type {
+	case schema.HasOne:
+		association.Kind = HasOne
+	case schema.HasMany:
+		association.Kind = HasMany
+	case schema.BelongsTo:
+		association.Kind = BelongsTo
+	case schema.Many2Many:
+		association.Kind = Many2Many
+	}
+	if rel.FieldSchema != nil {
+		association.Table = rel.FieldSchema.Table
+	}
+	if rel.JoinTable != nil {
+		association.JoinTable = rel.JoinTable.Table
+	}
+	if rel.Polymorphic != nil {
+		association.Polymorphic = &Polymorphic{
+			Value: rel.Polymorphic.Value,
+		}
+		if rel.Polymorphic.PolymorphicType != nil {
+			association.Polymorphic.TypeField = rel.Polymorphic.PolymorphicType.DBName
+		}
+		if rel.Polymorphic.PolymorphicID != nil {
+			association.Polymorphic.IDField = rel.Polymorphic.PolymorphicID.DBName
+		}
+	}
+	for _, ref := range rel.References {
+		var key = AssociationKey{}
+		if ref.ForeignKey != nil {
+			key.ForeignKey = qualifiedColumn(ref.ForeignKey)
+		}
+		if ref.PrimaryKey != nil {
+			key.References = qualifiedColumn(ref.PrimaryKey)
+		} else {
+			key.Value = ref.PrimaryValue
+		}
+		association.Keys = append(association.Keys, key)
+	}
+	return association, true
+}
+
+func qualifiedColumn(field *schema.Field) string {
+	if field.Schema != nil && field.Schema.Table != "" {
+		return field.Schema.Table + "." + field.DBName
+	}
+	return field.DBName
+}
+
+// Relation returns a readable relationship kind, e.g. "has-many (polymorphic)".
+func (a Association) Relation() string {
+	if a.Polymorphic != nil {
+		return string(a.Kind) + " (polymorphic)"
+	}
+	return string(a.Kind)
+}
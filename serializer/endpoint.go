@@ -0,0 +1,275 @@
+package serializer
+
+import (
+	"fmt"
+	"github.com/getevo/restify"
+	"regexp"
+	"strings"
+)
+
+const (
+	ContentTypeJSON      = "application/json"
+	ContentTypeForm      = "application/x-www-form-urlencoded"
+	ContentTypeMultipart = "multipart/form-data"
+)
+
+// FilterOperators are the operators accepted by restify filters, as in `field[op]=value`.
+var FilterOperators = []string{"eq", "neq", "gt", "gte", "lt", "lte", "in", "notin", "between", "contains", "search", "isnull", "notnull"}
+
+type BodyVariant string
+
+const (
+	BodyCreate BodyVariant = "create"
+	BodyUpdate BodyVariant = "update"
+)
+
+type ResponseKind string
+
+const (
+	ResponseSingle          ResponseKind = "single"
+	ResponseList            ResponseKind = "list"
+	ResponsePaginated       ResponseKind = "paginated"
+	ResponseBatchCreate     ResponseKind = "batch-create"
+	ResponseBatchUpdate     ResponseKind = "batch-update"
+	ResponseDelete          ResponseKind = "delete"
+	ResponseAggregate       ResponseKind = "aggregate"
+	ResponseModelInfo       ResponseKind = "model-info"
+	ResponseValidationError ResponseKind = "validation-error"
+	ResponseError           ResponseKind = "error"
+)
+
+// Operation is a restify endpoint described once for every generator: its parameters,
+// accepted bodies and responses.
+type Operation struct {
+	Name        string            `json:"name"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	Description string            `json:"description"`
+	PathParams  []Parameter       `json:"path_params"`
+	QueryParams []Parameter       `json:"query_params"`
+	Body        *Body             `json:"body,omitempty"`
+	Responses   []Response        `json:"responses"`
+	Endpoint    *restify.Endpoint `json:"-"`
+}
+
+// Parameter is a path or query parameter. Filters are deep objects such as `price[gte]=10`.
+type Parameter struct {
+	Name        string   `json:"name"`
+	In          string   `json:"in"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Format      string   `json:"format,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Example     string   `json:"example,omitempty"`
+	Required    bool     `json:"required"`
+	Style       string   `json:"style,omitempty"`
+	Field       *Field   `json:"-"`
+}
+
+// Body is the request body accepted by an operation.
+type Body struct {
+	Variant      BodyVariant `json:"variant"`
+	Batch        bool        `json:"batch"`
+	ContentTypes []string    `json:"content_types"`
+}
+
+// Response is a possible response of an operation.
+type Response struct {
+	Status      string       `json:"status"`
+	Description string       `json:"description"`
+	Kind        ResponseKind `json:"kind"`
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// NewOperations describes the restify endpoints of an entity.
+func NewOperations(entity *Entity) []Operation {
+	var operations []Operation
+	for _, endpoint := range entity.Endpoints {
+		operations = append(operations, NewOperation(entity, endpoint))
+	}
+	return operations
+}
+
+// NewOperation describes a restify endpoint of an entity from its flags.
+func NewOperation(entity *Entity, endpoint *restify.Endpoint) Operation {
+	var op = Operation{
+		Name:        endpoint.Name,
+		Method:      string(endpoint.Method),
+		Path:        endpoint.AbsoluteURI,
+		Description: endpoint.Description,
+		Endpoint:    endpoint,
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(endpoint.AbsoluteURI, -1) {
+		var param = Parameter{Name: match[1], In: "path", Type: "string", Required: true}
+		if field, ok := entity.fieldByColumn(match[1]); ok {
+			param.Type, param.Format, param.Field = field.JsonType, field.Format, &field
+			param.Description = "primary key " + field.JsonTag
+			if field.Description != "" {
+				param.Description += ": " + field.Description
+			}
+		}
+		op.PathParams = append(op.PathParams, param)
+	}
+
+	var batchUpdate = op.Method == "PATCH" && endpoint.Batch
+	switch {
+	case endpoint.AcceptData && endpoint.Batch:
+		op.Body = &Body{Variant: BodyCreate, Batch: true, ContentTypes: []string{ContentTypeJSON}}
+	case endpoint.AcceptData && op.Method == "PUT":
+		op.Body = &Body{Variant: BodyCreate, ContentTypes: []string{ContentTypeJSON, ContentTypeForm, ContentTypeMultipart}}
+	case endpoint.AcceptData || batchUpdate:
+		op.Body = &Body{Variant: BodyUpdate, ContentTypes: []string{ContentTypeJSON, ContentTypeForm, ContentTypeMultipart}}
+	}
+
+	op.QueryParams = queryParams(entity, endpoint, op)
+	op.Responses = responses(endpoint, op)
+	return op
+}
+
+func queryParams(entity *Entity, endpoint *restify.Endpoint, op Operation) []Parameter {
+	var params []Parameter
+	var read = op.Method == "GET"
+	if endpoint.Pagination {
+		params = append(params,
+			Parameter{Name: "page", In: "query", Type: "integer", Example: "1", Description: "page to load, starting at 1"},
+			Parameter{Name: "size", In: "query", Type: "integer", Example: "10", Description: "size of results, default 10, max 100"},
+		)
+	}
+	if endpoint.Filterable {
+		if read && !endpoint.PKUrl && !endpoint.Pagination {
+			params = append(params,
+				Parameter{Name: "offset", In: "query", Type: "integer", Example: "0", Description: "number of records to skip"},
+				Parameter{Name: "limit", In: "query", Type: "integer", Example: "10", Description: "maximum number of records to return"},
+			)
+		}
+		if read {
+			params = append(params,
+				Parameter{Name: "order", In: "query", Type: "string", Example: "id.desc", Description: "sort results by field, as field.asc or field.desc; accepts comma separated values"},
+				Parameter{Name: "fields", In: "query", Type: "string", Example: "id,name", Description: "return only the given comma separated fields"},
+			)
+		}
+		if read && len(entity.Association) > 0 {
+			var names []string
+			for _, association := range entity.Association {
+				names = append(names, association.Name)
+			}
+			params = append(params, Parameter{
+				Name:        "associations",
+				In:          "query",
+				Type:        "string",
+				Example:     names[0],
+				Description: fmt.Sprintf("load associations: %s; accepts comma separated values, `1` for all and `deep` for nested ones", strings.Join(names, ", ")),
+			})
+		}
+		for i := range entity.Fields {
+			var field = entity.Fields[i]
+			if !field.InResponse() {
+				continue
+			}
+			params = append(params, Parameter{
+				Name:        field.DBName,
+				In:          "query",
+				Type:        "object",
+				Style:       "deepObject",
+				Example:     field.DBName + "[eq]=value",
+				Description: fmt.Sprintf("filter results by %s, as %s[op]=value where op is one of %s", field.JsonTag, field.DBName, strings.Join(FilterOperators, ", ")),
+				Field:       &field,
+			})
+		}
+		if !read {
+			params = append(params, Parameter{Name: "unsafe", In: "query", Type: "boolean", Example: "1", Description: "allow the operation on all records when no filter is given"})
+		}
+	}
+	if op.Body != nil && endpoint.Batch && (op.Method == "PATCH" || op.Method == "POST") {
+		params = append(params, Parameter{Name: "return", In: "query", Type: "boolean", Example: "1", Description: "return the affected records"})
+	}
+	params = append(params, Parameter{Name: "debug", In: "query", Type: "string", Enum: []string{"restify"}, Example: "restify", Description: "log the executed queries"})
+	return withoutCollidingFilters(params)
+}
+
+// withoutCollidingFilters drops the filters of columns named like a restify parameter, e.g. an
+// `order` column, which would declare the same query parameter twice. The restify parameter
+// tells how to filter the column instead.
+func withoutCollidingFilters(params []Parameter) []Parameter {
+	var fixed = map[string]int{}
+	for i, param := range params {
+		if param.Style != "deepObject" {
+			fixed[param.Name] = i
+		}
+	}
+	var result []Parameter
+	for _, param := range params {
+		if i, ok := fixed[param.Name]; ok && param.Style == "deepObject" {
+			params[i].Description += fmt.Sprintf("; the %s column is filtered as %s[op]=value", param.Name, param.Name)
+		}
+	}
+	for _, param := range params {
+		if _, ok := fixed[param.Name]; !ok || param.Style != "deepObject" {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+func responses(endpoint *restify.Endpoint, op Operation) []Response {
+	var success = Response{Status: "200", Description: "OK"}
+	switch {
+	case op.Method == "DELETE":
+		success.Kind = ResponseDelete
+	case endpoint.Pagination:
+		success.Kind = ResponsePaginated
+	case strings.EqualFold(endpoint.Name, "AGGREGATE"):
+		success.Kind = ResponseAggregate
+	case op.Method == "GET" && !endpoint.Filterable:
+		success.Kind = ResponseModelInfo
+	case op.Method == "GET" && !endpoint.PKUrl:
+		success.Kind = ResponseList
+	case endpoint.Batch && op.Method == "PATCH":
+		// restify answers with the applied values, or the updated records with ?return=1
+		success.Kind = ResponseBatchUpdate
+	case endpoint.Batch:
+		success.Kind = ResponseBatchCreate
+	default:
+		success.Kind = ResponseSingle
+	}
+
+	var list = []Response{success}
+	if (endpoint.Filterable && op.Method != "GET") || op.Body != nil {
+		list = append(list, Response{Status: "400", Description: "Bad request, such as an unsafe request without filters", Kind: ResponseError})
+	}
+	list = append(list, Response{Status: "403", Description: "Permission denied", Kind: ResponseError})
+	if endpoint.PKUrl {
+		list = append(list, Response{Status: "404", Description: "Object does not exist", Kind: ResponseError})
+	}
+	if op.Body != nil {
+		list = append(list, Response{Status: "412", Description: "Data validation error", Kind: ResponseValidationError})
+	}
+	list = append(list, Response{Status: "500", Description: "Server error", Kind: ResponseError})
+	return list
+}
+
+// OpenAPIPath returns the path with parameters written as {name}.
+func (op Operation) OpenAPIPath() string {
+	return pathParam.ReplaceAllString(op.Path, "{$1}")
+}
+
+// Response returns the response for a status code.
+func (op Operation) Response(status string) (Response, bool) {
+	for _, response := range op.Responses {
+		if response.Status == status {
+			return response, true
+		}
+	}
+	return Response{}, false
+}
+
+func (e *Entity) fieldByColumn(column string) (Field, bool) {
+	for _, field := range e.Fields {
+		if field.DBName == column {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,54 @@
+package serializer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getevo/restify"
+)
+
+func column(name, jsonType string) Field {
+	return Field{Name: name, JsonTag: name, DBName: name, JsonType: jsonType, Visibility: Visibility{Readable: true}}
+}
+
+func TestNewOperationParameters(t *testing.T) {
+	var entity = Entity{Fields: []Field{column("order_id", "integer"), column("user_id", "integer"), column("total", "number")}}
+	var op = NewOperation(&entity, &restify.Endpoint{Name: "GET", Method: "GET", PKUrl: true, Filterable: true, AbsoluteURI: "/orders/get/:order_id/:user_id"})
+
+	if op.OpenAPIPath() != "/orders/get/{order_id}/{user_id}" {
+		t.Errorf("OpenAPIPath() = %s", op.OpenAPIPath())
+	}
+	if len(op.PathParams) != 2 || op.PathParams[0].Type != "integer" || !op.PathParams[1].Required {
+		t.Errorf("composite keys are not typed path parameters: %+v", op.PathParams)
+	}
+	var filters = 0
+	for _, param := range op.QueryParams {
+		if param.Style == "deepObject" {
+			filters++
+		}
+	}
+	if filters != 3 {
+		t.Errorf("got %d filters, want one per column", filters)
+	}
+}
+
+func TestQueryParamsCollidingColumns(t *testing.T) {
+	var entity = Entity{Fields: []Field{column("id", "integer"), column("order", "integer"), column("size", "string")}}
+	var op = NewOperation(&entity, &restify.Endpoint{Name: "PAGINATE", Method: "GET", Filterable: true, Pagination: true, AbsoluteURI: "/items/paginate"})
+
+	var seen = map[string]Parameter{}
+	for _, param := range op.QueryParams {
+		if _, ok := seen[param.Name]; ok {
+			t.Fatalf("query parameter %s is declared twice", param.Name)
+		}
+		seen[param.Name] = param
+	}
+	for _, name := range []string{"order", "size"} {
+		if param := seen[name]; param.Style == "deepObject" || !strings.Contains(param.Description, name+"[op]=value") {
+			t.Errorf("%s must stay the restify parameter and tell how to filter the column: %+v", name, param)
+		}
+	}
+	if seen["id"].Style != "deepObject" {
+		t.Errorf("other columns keep their filter: %+v", seen["id"])
+	}
+}
@@ -0,0 +1,77 @@
+package serializer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Generator renders the documentation in one format. Generators are registered by name and
+// write their files through the Writer, which records them.
+type Generator interface {
+	Name() string
+	Generate(doc *Doc, w Writer) error
+}
+
+// Writer receives the files of a generator.
+type Writer interface {
+	// Options returns the build options
+	Options() Options
+	// Option returns an option of the generator from the generator_options of project.yml
+	Option(key string) string
+	// Create opens a file of the output directory for writing
+	Create(name string) (io.WriteCloser, error)
+	// Write writes a whole file of the output directory
+	Write(name string, data []byte) error
+}
+
+// FileWriter writes the files of a generator under the output directory and records their paths.
+type FileWriter struct {
+	options  Options
+	settings map[string]string
+	files    []string
+}
+
+// NewFileWriter returns a writer for a generator with the given options from project.yml.
+func NewFileWriter(opts Options, settings map[string]string) *FileWriter {
+	return &FileWriter{options: opts.WithDefaults(), settings: settings}
+}
+
+func (w *FileWriter) Options() Options {
+	return w.options
+}
+
+func (w *FileWriter) Option(key string) string {
+	return w.settings[key]
+}
+
+func (w *FileWriter) Create(name string) (io.WriteCloser, error) {
+	var path = w.options.Output(name)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w.files = append(w.files, path)
+	return file, nil
+}
+
+func (w *FileWriter) Write(name string, data []byte) error {
+	file, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(file, bytes.NewReader(data)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Files returns the paths written so far.
+func (w *FileWriter) Files() []string {
+	return w.files
+}
@@ -0,0 +1,63 @@
+package serializer
+
+import (
+	"github.com/getevo/restify"
+	"gorm.io/gorm"
+	"path/filepath"
+	"strings"
+)
+
+// Options configure how the documentation is built and where the generators write it.
+type Options struct {
+	// ProjectFile holds the title, description and settings, "project.yml" by default
+	ProjectFile string
+	// OutputDir receives the generated files, "./docify" by default
+	OutputDir string
+	// Seed overrides the seed of the project file when set
+	Seed *int64
+	// Resources to document, all restify resources by default
+	Resources []*restify.Resource
+	// DB is read for sample rows, the evo database by default when one is registered
+	DB *gorm.DB
+	// Generators to run, in order, overriding the generators of the project file
+	Generators []string
+}
+
+// WithDefaults returns the options with empty values set to their defaults.
+func (o Options) WithDefaults() Options {
+	if o.ProjectFile == "" {
+		o.ProjectFile = "project.yml"
+	}
+	if o.OutputDir == "" {
+		o.OutputDir = "./docify"
+	}
+	return o
+}
+
+// Output returns the path of a generated file.
+func (o Options) Output(name string) string {
+	return filepath.Join(o.WithDefaults().OutputDir, name)
+}
+
+// GeneratorNames returns the generators to run in order, from the options or else from the
+// project settings; nil stands for all registered generators.
+func (o Options) GeneratorNames(settings Settings) []string {
+	if len(o.Generators) > 0 {
+		return o.Generators
+	}
+	return settings.Generators
+}
+
+// Runs reports whether the named generator is part of the run.
+func (o Options) Runs(settings Settings, name string) bool {
+	var names = o.GeneratorNames(settings)
+	if len(names) == 0 {
+		return true
+	}
+	for _, item := range names {
+		if strings.TrimSpace(item) == name {
+			return true
+		}
+	}
+	return false
+}
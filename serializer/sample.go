@@ -0,0 +1,181 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DataSample holds the sample payloads of an entity. Generators render them as strict JSON,
+// JSON with comments or form bodies as their output format requires.
+type DataSample struct {
+	Create   SampleObject `json:"create"`
+	Update   SampleObject `json:"update"`
+	Response SampleObject `json:"response"`
+}
+
+// Batch returns the body of batch create requests.
+func (d DataSample) Batch() SampleList {
+	return SampleList{d.Create}
+}
+
+// List returns the body of responses holding multiple records.
+func (d DataSample) List() SampleList {
+	return SampleList{d.Response}
+}
+
+// SampleProperty is a property of a sample object along with its annotation.
+type SampleProperty struct {
+	Name    string      `json:"name"`
+	Value   interface{} `json:"value"`
+	Comment string      `json:"comment,omitempty"`
+}
+
+// SampleObject is a JSON object whose properties keep their declaration order.
+type SampleObject []SampleProperty
+
+type SampleList []SampleObject
+
+// NewSampleObject decodes a JSON object keeping the order of its properties.
+func NewSampleObject(data []byte) (SampleObject, error) {
+	var object SampleObject
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("sample is not a JSON object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		object = append(object, SampleProperty{Name: token.(string), Value: value})
+	}
+	return object, nil
+}
+
+// Get returns the value of a property.
+func (o SampleObject) Get(name string) (interface{}, bool) {
+	for _, property := range o {
+		if property.Name == name {
+			return property.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Annotate sets the comment of a property.
+func (o SampleObject) Annotate(name, comment string) {
+	for i := range o {
+		if o[i].Name == name {
+			o[i].Comment = comment
+		}
+	}
+}
+
+// MarshalJSON encodes the object with its properties in order.
+func (o SampleObject) MarshalJSON() ([]byte, error) {
+	var buffer = bytes.NewBufferString("{")
+	for i, property := range o {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, _ := json.Marshal(property.Name)
+		value, err := json.Marshal(property.Value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// JSON returns the object as indented, strict JSON.
+func (o SampleObject) JSON() string {
+	return indentJSON(o)
+}
+
+// CommentedJSON returns the object as indented JSON with each annotation as a trailing // comment.
+// The result is meant for reading and is not valid JSON.
+func (o SampleObject) CommentedJSON() string {
+	if len(o) == 0 {
+		return "{}"
+	}
+	var lines = []string{"{"}
+	for i, property := range o {
+		var value = shift(indentJSON(property.Value))
+		var line = "\t" + indentJSON(property.Name) + ": " + strings.TrimPrefix(value, "\t")
+		if i < len(o)-1 {
+			line += ","
+		}
+		if property.Comment != "" {
+			line += " // " + strings.ReplaceAll(property.Comment, "\n", " ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+// Form returns the object as form values. Objects and arrays are sent as JSON strings.
+func (o SampleObject) Form() url.Values {
+	var values = url.Values{}
+	for _, property := range o {
+		values.Set(property.Name, formValue(property.Value))
+	}
+	return values
+}
+
+// Encode returns the object as an application/x-www-form-urlencoded body, keeping property order.
+func (o SampleObject) Encode() string {
+	var pairs []string
+	for _, property := range o {
+		pairs = append(pairs, url.QueryEscape(property.Name)+"="+url.QueryEscape(formValue(property.Value)))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// JSON returns the list as indented, strict JSON.
+func (l SampleList) JSON() string {
+	return indentJSON(l)
+}
+
+// CommentedJSON returns the list as indented JSON with annotations, see SampleObject.CommentedJSON.
+func (l SampleList) CommentedJSON() string {
+	var items []string
+	for _, object := range l {
+		items = append(items, shift(object.CommentedJSON()))
+	}
+	return "[\n" + strings.Join(items, ",\n") + "\n]"
+}
+
+func indentJSON(v interface{}) string {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return "null"
+	}
+	return string(b)
+}
+
+func formValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return s
+	}
+	return string(b)
+}
+
+func shift(s string) string {
+	return "\t" + strings.Join(strings.Split(s, "\n"), "\n\t")
+}
@@ -9,9 +9,27 @@ import (
 type Doc struct {
 	Title       string   `json:"title"`
 	Description string   `json:"description"`
+	Settings    Settings `json:"settings"`
 	Entities    []Entity `json:"entities"`
 }
 
+// Settings are generator options read from the settings section of project.yml
+type Settings struct {
+	ExpandEmbedded bool `json:"expand_embedded" yaml:"expand_embedded"`
+	// Seed for generated sample data; the same seed and models always produce the same samples
+	Seed int64 `json:"seed" yaml:"seed"`
+	// DisableDBSamples stops reading sample rows from the database, all samples are generated
+	DisableDBSamples bool `json:"disable_db_samples" yaml:"disable_db_samples"`
+	// SkipDBSamples lists entities (name, id or table) whose samples are never read from the database
+	SkipDBSamples []string `json:"skip_db_samples" yaml:"skip_db_samples"`
+	// Sensitive marks fields by name pattern whose database values must not be published
+	Sensitive []SensitiveRule `json:"sensitive" yaml:"sensitive"`
+	// Generators lists the generators to run, in order; all registered generators by default
+	Generators []string `json:"generators" yaml:"generators"`
+	// GeneratorOptions holds the options of each generator, keyed by generator name
+	GeneratorOptions map[string]map[string]string `json:"generator_options" yaml:"generator_options"`
+}
+
 func (d *Doc) ParseYaml(s string) error {
 	data, err := os.ReadFile(s)
 	if err != nil {
@@ -30,39 +48,102 @@ type Entity struct {
 	Fields      []Field             `json:"fields"`
 	Association []Association       `json:"associations"`
 	Endpoints   []*restify.Endpoint `json:"endpoints"`
+	Operations  []Operation         `json:"operations"`
 	PrimaryKey  []Field             `json:"primary_key"`
+	Indexes     []Index             `json:"indexes"`
 	Definition  *StructDefinition   `json:"definition"`
 	Resource    *restify.Resource   `json:"resource"`
 	DataSample  DataSample          `json:"data_sample"`
 }
 
 type Field struct {
-	Name          string      `json:"name"`
-	Description   string      `json:"description"`
-	JsonTag       string      `json:"json_tag"`
-	JsonType      string      `json:"json_type"`
-	DBType        string      `json:"db_type"`
-	GoType        string      `json:"go_type"`
-	DBName        string      `json:"db_name"`
-	Validation    string      `json:"validation"`
-	PrimaryKey    bool        `json:"primary_key"`
-	AutoIncrement bool        `json:"auto_increment"`
-	Nullable      bool        `json:"nullable"`
-	Unique        bool        `json:"unique"`
-	UniqueIndex   string      `json:"unique_index"`
-	Default       string      `json:"default"`
-	Enum          []string    `json:"enum"`
-	Indexed       bool        `json:"indexed"`
-	Index         string      `json:"index"`
-	ForeignKey    *ForeignKey `json:"foreign_key"`
-	SampleData    interface{} `json:"sample_data"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	JsonTag       string          `json:"json_tag"`
+	JsonType      string          `json:"json_type"`
+	Format        string          `json:"format,omitempty"`
+	Example       interface{}     `json:"example,omitempty"`
+	DBType        string          `json:"db_type"`
+	GoType        string          `json:"go_type"`
+	DBName        string          `json:"db_name"`
+	Validation    string          `json:"validation"`
+	Rules         ValidationRules `json:"rules,omitempty"`
+	PrimaryKey    bool            `json:"primary_key"`
+	AutoIncrement bool            `json:"auto_increment"`
+	Nullable      bool            `json:"nullable"`
+	Unique        bool            `json:"unique"`
+	UniqueIndex   string          `json:"unique_index"`
+	Size          int             `json:"size"`
+	Precision     int             `json:"precision"`
+	Scale         int             `json:"scale"`
+	Default       string          `json:"default"`
+	Enum          []string        `json:"enum"`
+	EnumValues    []EnumValue     `json:"enum_values,omitempty"`
+	Indexed       bool            `json:"indexed"`
+	Index         string          `json:"index"`
+	ForeignKey    *ForeignKey     `json:"foreign_key"`
+	Visibility    Visibility      `json:"visibility"`
+	Sensitive     Sensitivity     `json:"sensitive,omitempty"`
+	SampleData    interface{}     `json:"sample_data"`
+	Properties    []Field         `json:"properties,omitempty"`
+	Items         *Field          `json:"items,omitempty"`
+	Values        *Field          `json:"values,omitempty"`
+}
+
+type EnumValue struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+type Index struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Class   string   `json:"class"`
+	Type    string   `json:"type"`
+	Comment string   `json:"comment"`
+	Fields  []string `json:"fields"`
 }
 
 type Association struct {
-	Name       string  `json:"name"`
-	EntityName string  `json:"entity_name"`
-	Entity     *Entity `json:"entity"`
-	Array      bool    `json:"array"`
+	Name        string           `json:"name"`
+	JsonTag     string           `json:"json_tag"`
+	EntityName  string           `json:"entity_name"`
+	Entity      *Entity          `json:"entity"`
+	Array       bool             `json:"array"`
+	Kind        RelationKind     `json:"kind"`
+	Table       string           `json:"table"`
+	JoinTable   string           `json:"join_table"`
+	Keys        []AssociationKey `json:"keys"`
+	Polymorphic *Polymorphic     `json:"polymorphic"`
+}
+
+type AssociationKey struct {
+	ForeignKey string `json:"foreign_key"`
+	References string `json:"references"`
+	Value      string `json:"value"`
+}
+
+type Polymorphic struct {
+	TypeField string `json:"type_field"`
+	IDField   string `json:"id_field"`
+	Value     string `json:"value"`
+}
+
+// Sensitivity tells how a value read from the database is protected before it is published
+type Sensitivity string
+
+const (
+	SensitivityNone Sensitivity = ""
+	SensitivityMask Sensitivity = "mask"
+	SensitivityFake Sensitivity = "fake"
+)
+
+// SensitiveRule applies a sensitivity to fields whose json or column name matches Pattern.
+// Action is mask, fake, or none/public to publish the value unchanged; an empty action masks.
+type SensitiveRule struct {
+	Pattern string      `json:"pattern" yaml:"pattern"`
+	Action  Sensitivity `json:"action" yaml:"action"`
 }
 
 type ForeignKey struct {
@@ -72,10 +153,11 @@ type ForeignKey struct {
 }
 
 type StructDefinition struct {
-	File        string
-	Description string
-	Body        string
-	Fields      []FieldDefinition
+	File         string
+	Description  string
+	Body         string
+	ExpandedBody string
+	Fields       []FieldDefinition
 }
 
 type FieldDefinition struct {
@@ -83,12 +165,6 @@ type FieldDefinition struct {
 	Type        string
 	Tag         string
 	Description string
-}
-
-type DataSample struct {
-	CreateJSON           string `json:"create_json"`
-	UpdateJSON           string `json:"update_json"`
-	BatchJSON            string `json:"batch_json"`
-	SingleResponseJSON   string `json:"single_response_json"`
-	MultipleResponseJSON string `json:"multiple_response_json"`
+	Embedded    bool
+	Origin      string
 }
@@ -0,0 +1,162 @@
+package serializer
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sync"
+)
+
+// TypeMapping describes how values of a Go type look in JSON.
+type TypeMapping struct {
+	JsonType    string      `json:"json_type"`
+	Format      string      `json:"format,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+var typeMappings = map[string]TypeMapping{}
+var typeMappingsMu sync.RWMutex
+
+func init() {
+	for name, mapping := range map[string]TypeMapping{
+		"time.Time":           {JsonType: "string", Format: "date-time", Example: "2024-01-01T00:00:00Z"},
+		"time.Duration":       {JsonType: "integer", Format: "int64", Example: 1000000000, Description: "duration in nanoseconds"},
+		"gorm.DeletedAt":      {JsonType: "string", Format: "date-time", Example: nil, Description: "time of soft deletion, null when not deleted"},
+		"decimal.Decimal":     {JsonType: "string", Format: "decimal", Example: "10.50"},
+		"decimal.NullDecimal": {JsonType: "string", Format: "decimal", Example: "10.50"},
+		"uuid.UUID":           {JsonType: "string", Format: "uuid", Example: "3fa85f64-5717-4562-b3fc-2c963f66afa6"},
+		"uuid.NullUUID":       {JsonType: "string", Format: "uuid", Example: "3fa85f64-5717-4562-b3fc-2c963f66afa6"},
+		"datatypes.Date":      {JsonType: "string", Format: "date", Example: "2024-01-01"},
+		"datatypes.Time":      {JsonType: "string", Format: "time", Example: "15:04:05"},
+		"datatypes.JSON":      {JsonType: "object"},
+		"json.RawMessage":     {JsonType: "object"},
+		"net.IP":              {JsonType: "string", Example: "192.168.1.1", Description: "IPv4 or IPv6 address"},
+		"sql.NullString":      {JsonType: "string"},
+		"sql.NullBool":        {JsonType: "boolean"},
+		"sql.NullByte":        {JsonType: "integer", Format: "int32"},
+		"sql.NullInt16":       {JsonType: "integer", Format: "int32"},
+		"sql.NullInt32":       {JsonType: "integer", Format: "int32"},
+		"sql.NullInt64":       {JsonType: "integer", Format: "int64"},
+		"sql.NullFloat64":     {JsonType: "number", Format: "double"},
+		"sql.NullTime":        {JsonType: "string", Format: "date-time", Example: "2024-01-01T00:00:00Z"},
+	} {
+		typeMappings[name] = mapping
+	}
+}
+
+// RegisterType maps a Go type to its JSON representation in every generator, e.g. for an
+// application's own ID, money or Scanner/Valuer types.
+func RegisterType(t reflect.Type, mapping TypeMapping) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	RegisterTypeName(t.String(), mapping)
+}
+
+// RegisterTypeName maps a Go type given by its package qualified name, e.g. "uuid.UUID",
+// without importing the package declaring it.
+func RegisterTypeName(name string, mapping TypeMapping) {
+	typeMappingsMu.Lock()
+	defer typeMappingsMu.Unlock()
+	typeMappings[name] = mapping
+}
+
+// LookupType returns the registered mapping of a type.
+func LookupType(t reflect.Type) (TypeMapping, bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	typeMappingsMu.RLock()
+	defer typeMappingsMu.RUnlock()
+	mapping, ok := typeMappings[t.String()]
+	return mapping, ok
+}
+
+// MapType returns the JSON representation of a type, from the registry or from its kind.
+func MapType(t reflect.Type) TypeMapping {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if mapping, ok := LookupType(t); ok {
+		return mapping
+	}
+	if mapping, ok := marshalerMapping(t); ok {
+		return mapping
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return TypeMapping{JsonType: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return TypeMapping{JsonType: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return TypeMapping{JsonType: "integer", Format: "int64"}
+	case reflect.Float32:
+		return TypeMapping{JsonType: "number", Format: "float"}
+	case reflect.Float64:
+		return TypeMapping{JsonType: "number", Format: "double"}
+	case reflect.String:
+		return TypeMapping{JsonType: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return TypeMapping{JsonType: "string", Format: "byte"}
+		}
+		return TypeMapping{JsonType: "array"}
+	case reflect.Map, reflect.Struct:
+		return TypeMapping{JsonType: "object"}
+	case reflect.Interface:
+		return TypeMapping{}
+	}
+	return TypeMapping{JsonType: "string"}
+}
+
+// marshalerMapping maps struct types encoding themselves. Text marshalers are strings. The JSON
+// of other marshalers is unknown and taken as an object, unless they are also sql Valuers,
+// such as null wrappers, whose JSON follows the database value.
+func marshalerMapping(t reflect.Type) (TypeMapping, bool) {
+	if t.Kind() != reflect.Struct {
+		return TypeMapping{}, false
+	}
+	var implements = func(i reflect.Type) bool {
+		return t.Implements(i) || reflect.PointerTo(t).Implements(i)
+	}
+	switch {
+	case implements(jsonMarshaler):
+		if mapping, ok := valuerMapping(t); ok {
+			return mapping, true
+		}
+		return TypeMapping{JsonType: "object"}, true
+	case implements(textMarshaler):
+		return TypeMapping{JsonType: "string"}, true
+	}
+	return TypeMapping{}, false
+}
+
+// valuerMapping maps a driver.Valuer by the value it stores, or by the value field of a
+// sql.Null* like struct when the zero value is NULL.
+func valuerMapping(t reflect.Type) (mapping TypeMapping, ok bool) {
+	var ptr = reflect.New(t)
+	valuer, isValuer := ptr.Interface().(driver.Valuer)
+	if !isValuer {
+		return TypeMapping{}, false
+	}
+	defer func() {
+		if recover() != nil {
+			mapping, ok = TypeMapping{}, false
+		}
+	}()
+	if value, err := valuer.Value(); err == nil && value != nil {
+		var mapped = MapType(reflect.TypeOf(value))
+		return mapped, mapped.JsonType != "" && mapped.JsonType != "object"
+	}
+	if t.NumField() != 2 {
+		return TypeMapping{}, false
+	}
+	for i := 0; i < 2; i++ {
+		var valid = t.Field(1 - i)
+		if valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool {
+			return MapType(t.Field(i).Type), true
+		}
+	}
+	return TypeMapping{}, false
+}
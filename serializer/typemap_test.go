@@ -0,0 +1,99 @@
+package serializer
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// textID marshals to a JSON string through encoding.TextMarshaler.
+type textID struct{ n int }
+
+func (id textID) MarshalText() ([]byte, error) { return []byte("id"), nil }
+
+// point marshals to a JSON object of its own shape.
+type point struct{ x, y int }
+
+func (p point) MarshalJSON() ([]byte, error) { return json.Marshal(map[string]int{"x": p.x, "y": p.y}) }
+
+// nullInt follows the sql.Null* shape and marshals to its value or null.
+type nullInt struct {
+	Int   int64
+	Valid bool
+}
+
+func (n nullInt) MarshalJSON() ([]byte, error) { return json.Marshal(n.Int) }
+func (n nullInt) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Int, nil
+}
+
+// money is stored as its amount in cents.
+type money struct{ cents int64 }
+
+func (m money) MarshalJSON() ([]byte, error) { return json.Marshal(m.cents) }
+func (m money) Value() (driver.Value, error) { return float64(m.cents) / 100, nil }
+
+type registeredID struct{ n int }
+
+func TestMapType(t *testing.T) {
+	RegisterType(reflect.TypeOf(registeredID{}), TypeMapping{JsonType: "string", Format: "uuid"})
+
+	var tests = []struct {
+		value  interface{}
+		want   string
+		format string
+	}{
+		{true, "boolean", ""},
+		{int8(1), "integer", "int32"},
+		{uint(1), "integer", "int64"},
+		{float32(1), "number", "float"},
+		{"", "string", ""},
+		{[]byte{}, "string", "byte"},
+		{[]int{}, "array", ""},
+		{map[string]int{}, "object", ""},
+		{struct{}{}, "object", ""},
+		{time.Time{}, "string", "date-time"},
+		{sql.NullInt64{}, "integer", "int64"},
+		{net.IP{}, "string", ""},
+		{textID{}, "string", ""},
+		{point{}, "object", ""},
+		{nullInt{}, "integer", "int64"},
+		{money{}, "number", "double"},
+		{&registeredID{}, "string", "uuid"},
+	}
+	for _, test := range tests {
+		var got = MapType(reflect.TypeOf(test.value))
+		if got.JsonType != test.want || got.Format != test.format {
+			t.Errorf("MapType(%T) = %s/%s, want %s/%s", test.value, got.JsonType, got.Format, test.want, test.format)
+		}
+	}
+}
+
+func TestDescribeTypeMarshalers(t *testing.T) {
+	type shape struct {
+		Name  string   `json:"name"`
+		Point point    `json:"point"`
+		ID    textID   `json:"id"`
+		Tags  []string `json:"tags"`
+	}
+	var field = DescribeType(reflect.TypeOf(shape{}))
+	if field.JsonType != "object" || len(field.Properties) != 4 {
+		t.Fatalf("DescribeType(shape) = %+v", field)
+	}
+	var want = map[string]string{"name": "string", "point": "object", "id": "string", "tags": "array"}
+	for _, property := range field.Properties {
+		if property.JsonType != want[property.JsonTag] {
+			t.Errorf("property %s is %s, want %s", property.JsonTag, property.JsonType, want[property.JsonTag])
+		}
+		if property.JsonTag == "point" && len(property.Properties) > 0 {
+			t.Errorf("JSON marshalers must not be expanded from their fields")
+		}
+	}
+}
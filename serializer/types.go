@@ -0,0 +1,139 @@
+package serializer
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// DescribeType describes the JSON shape of a Go type. Structs, slices, maps and JSON column
+// wrappers such as datatypes.JSONType[T] are expanded recursively into Properties, Items and Values.
+func DescribeType(t reflect.Type) Field {
+	return describeType(t, map[reflect.Type]bool{})
+}
+
+func describeType(t reflect.Type, seen map[reflect.Type]bool) Field {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var field = Field{
+		GoType: t.String(),
+	}
+
+	if inner := ValueType(t); inner != t {
+		var described = describeType(inner, seen)
+		described.GoType = field.GoType
+		return described
+	}
+
+	if mapping, ok := LookupType(t); ok {
+		field.JsonType = mapping.JsonType
+		field.Format = mapping.Format
+		return field
+	}
+
+	if t.Name() == "JSON" && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		field.JsonType = "object"
+		return field
+	}
+
+	// types encoding themselves can not be expanded from their fields
+	if mapping, ok := marshalerMapping(t); ok {
+		field.JsonType = mapping.JsonType
+		field.Format = mapping.Format
+		return field
+	}
+
+	var mapping = MapType(t)
+	field.JsonType = mapping.JsonType
+	field.Format = mapping.Format
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if field.JsonType != "array" {
+			break
+		}
+		var items = describeType(t.Elem(), seen)
+		field.Items = &items
+	case reflect.Map:
+		var values = describeType(t.Elem(), seen)
+		field.Values = &values
+	case reflect.Struct:
+		if seen[t] {
+			break
+		}
+		seen[t] = true
+		field.Properties = describeProperties(t, seen)
+		delete(seen, t)
+	}
+	return field
+}
+
+func describeProperties(t reflect.Type, seen map[reflect.Type]bool) []Field {
+	var properties []Field
+	for i := 0; i < t.NumField(); i++ {
+		var sf = t.Field(i)
+		var tag = sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		var name = strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			var ft = sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				properties = append(properties, describeProperties(ft, seen)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		var property = describeType(sf.Type, seen)
+		property.Name = sf.Name
+		property.JsonTag = name
+		property.Nullable = sf.Type.Kind() == reflect.Ptr
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// ValueType returns the type serialized by a JSON column wrapper such as datatypes.JSONType[T],
+// or t itself for any other type.
+func ValueType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t.NumField() == 1 && strings.HasPrefix(t.Name(), "JSONType[") {
+		return t.Field(0).Type
+	}
+	return t
+}
+
+// IsComplex reports whether the field describes an object or array value.
+func (f Field) IsComplex() bool {
+	return f.JsonType == "array" || (f.JsonType == "object" && (len(f.Properties) > 0 || f.Values != nil))
+}
+
+// TypeName returns a readable JSON type such as "array[object]" or "map[string]integer".
+func (f Field) TypeName() string {
+	switch {
+	case f.JsonType == "array" && f.Items != nil:
+		return "array[" + f.Items.TypeName() + "]"
+	case f.JsonType == "object" && f.Values != nil:
+		return "map[string]" + f.Values.TypeName()
+	case f.JsonType == "":
+		return "any"
+	}
+	return f.JsonType
+}
@@ -0,0 +1,373 @@
+package serializer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type RuleKind string
+
+const (
+	RuleRequired   RuleKind = "required"
+	RuleMinLength  RuleKind = "min_length"
+	RuleMaxLength  RuleKind = "max_length"
+	RuleLength     RuleKind = "length"
+	RuleNotLength  RuleKind = "not_length"
+	RuleMinimum    RuleKind = "minimum"
+	RuleMaximum    RuleKind = "maximum"
+	RuleEqual      RuleKind = "equal"
+	RuleNotEqual   RuleKind = "not_equal"
+	RulePattern    RuleKind = "pattern"
+	RuleFormat     RuleKind = "format"
+	RuleOneOf      RuleKind = "one_of"
+	RuleUnique     RuleKind = "unique"
+	RuleForeignKey RuleKind = "foreign_key"
+	RuleBefore     RuleKind = "before"
+	RuleAfter      RuleKind = "after"
+	RulePassword   RuleKind = "password"
+	RuleInteger    RuleKind = "integer"
+	RuleNumber     RuleKind = "number"
+	RuleCustom     RuleKind = "custom"
+)
+
+// ValidationRule is a single rule of a validation tag, e.g. `len>=3` or `email`.
+type ValidationRule struct {
+	Kind      RuleKind `json:"kind"`
+	Raw       string   `json:"raw"`
+	Value     string   `json:"value,omitempty"`
+	Number    float64  `json:"number,omitempty"`
+	Exclusive bool     `json:"exclusive,omitempty"`
+	Values    []string `json:"values,omitempty"`
+}
+
+type ValidationRules []ValidationRule
+
+var (
+	lenRule      = regexp.MustCompile(`(?i)^len(>|<|<=|>=|==|!=|<>|=)(\d+)$`)
+	numberRule   = regexp.MustCompile(`(?i)^(>|<|<=|>=|==|!=|<>|=)([+\-]?\d+(?:\.\d+)?)$`)
+	regexRule    = regexp.MustCompile(`(?i)^regex\((.*)\)$`)
+	passwordRule = regexp.MustCompile(`(?i)^password\((.*)\)$`)
+	signRule     = regexp.MustCompile(`(?i)^([+\-]?)(int|float)$`)
+	fieldRule    = regexp.MustCompile(`^(before|after)\((\w+)\)$`)
+	uniqueRule   = regexp.MustCompile(`^unique(?::(.+))?$`)
+)
+
+// formats maps validators to OpenAPI string formats.
+var formats = map[string]string{
+	"email":         "email",
+	"url":           "uri",
+	"domain":        "hostname",
+	"uuid":          "uuid",
+	"ip":            "ipv4",
+	"ipv4":          "ipv4",
+	"ip4":           "ipv4",
+	"ipv6":          "ipv6",
+	"ip6":           "ipv6",
+	"date":          "date",
+	"time":          "date-time",
+	"cidr":          "cidr",
+	"mac":           "mac",
+	"json":          "json",
+	"phone":         "phone",
+	"duration":      "duration",
+	"cron":          "cron",
+	"timezone":      "timezone",
+	"unixtimestamp": "unix-timestamp",
+	"unixts":        "unix-timestamp",
+	"isbn":          "isbn",
+	"isbn10":        "isbn10",
+	"isbn13":        "isbn13",
+	"creditcard":    "credit-card",
+	"rgbcolor":      "rgb-color",
+	"rgbacolor":     "rgba-color",
+	"hexcolor":      "hex-color",
+	"countryalpha2": "country-alpha2",
+	"countryalpha3": "country-alpha3",
+	"btcaddress":    "btc-address",
+	"ethaddress":    "eth-address",
+	"latitude":      "latitude",
+	"longitude":     "longitude",
+	"port":          "port",
+	"safehtml":      "safe-html",
+	"nohtml":        "no-html",
+	"text":          "text",
+	"name":          "name",
+	"latin":         "latin",
+	"uppercase":     "uppercase",
+	"lowercase":     "lowercase",
+	"e164":          "e164",
+	"alpha":         "alpha",
+	"alphanumeric":  "alphanumeric",
+	"digit":         "digit",
+	"slug":          "slug",
+	"hex":           "hex",
+}
+
+// patterns holds the regular expressions evo validators apply for some formats.
+var patterns = map[string]string{
+	"slug":         `^[a-z0-9_-]{1,200}$`,
+	"digit":        `^[0-9]+$`,
+	"alpha":        `^[a-zA-Z]+$`,
+	"alphanumeric": `^[a-zA-Z0-9]+$`,
+	"e164":         `^\+[1-9]\d{1,14}$`,
+	"hex":          `^[0-9a-fA-F]+$`,
+}
+
+// ParseValidation parses an evo/restify validation tag such as `required,len>=3,email` into rules.
+// Commas escaped with a backslash belong to the rule, as in evo.
+func ParseValidation(tag string) ValidationRules {
+	var rules ValidationRules
+	for _, raw := range splitValidators(tag) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		rules = append(rules, parseRule(raw))
+	}
+	return rules
+}
+
+func splitValidators(s string) []string {
+	var result []string
+	var buffer = ""
+	var lastChar rune
+	for _, c := range s {
+		if c == ',' && lastChar != '\\' {
+			result = append(result, buffer)
+			buffer = ""
+		} else {
+			buffer += string(c)
+		}
+		lastChar = c
+	}
+	if len(buffer) > 0 {
+		result = append(result, buffer)
+	}
+	return result
+}
+
+func parseRule(raw string) ValidationRule {
+	var rule = ValidationRule{Raw: raw, Kind: RuleCustom}
+	var lower = strings.ToLower(raw)
+	var key = strings.NewReplacer("-", "", "_", "").Replace(lower)
+
+	switch {
+	case lower == "required":
+		rule.Kind = RuleRequired
+	case lower == "fk":
+		rule.Kind = RuleForeignKey
+	case lower == "enum":
+		rule.Kind = RuleOneOf
+	case uniqueRule.MatchString(raw):
+		rule.Kind = RuleUnique
+		rule.Value = uniqueRule.FindStringSubmatch(raw)[1]
+	case fieldRule.MatchString(raw):
+		var match = fieldRule.FindStringSubmatch(raw)
+		rule.Kind = RuleBefore
+		if match[1] == "after" {
+			rule.Kind = RuleAfter
+		}
+		rule.Value = match[2]
+	case lenRule.MatchString(raw):
+		var match = lenRule.FindStringSubmatch(raw)
+		var n, _ = strconv.Atoi(match[2])
+		switch match[1] {
+		case ">":
+			rule.Kind, rule.Number = RuleMinLength, float64(n+1)
+		case ">=":
+			rule.Kind, rule.Number = RuleMinLength, float64(n)
+		case "<":
+			rule.Kind, rule.Number = RuleMaxLength, float64(n-1)
+		case "<=":
+			rule.Kind, rule.Number = RuleMaxLength, float64(n)
+		case "==", "=":
+			rule.Kind, rule.Number = RuleLength, float64(n)
+		default:
+			rule.Kind, rule.Number = RuleNotLength, float64(n)
+		}
+	case numberRule.MatchString(raw):
+		var match = numberRule.FindStringSubmatch(raw)
+		var n, _ = strconv.ParseFloat(match[2], 64)
+		rule.Number = n
+		switch match[1] {
+		case ">":
+			rule.Kind, rule.Exclusive = RuleMinimum, true
+		case ">=":
+			rule.Kind = RuleMinimum
+		case "<":
+			rule.Kind, rule.Exclusive = RuleMaximum, true
+		case "<=":
+			rule.Kind = RuleMaximum
+		case "==", "=":
+			rule.Kind = RuleEqual
+		default:
+			rule.Kind = RuleNotEqual
+		}
+	case regexRule.MatchString(raw):
+		rule.Kind = RulePattern
+		rule.Value = regexRule.FindStringSubmatch(raw)[1]
+	case passwordRule.MatchString(raw):
+		rule.Kind = RulePassword
+		rule.Value = strings.ToLower(passwordRule.FindStringSubmatch(raw)[1])
+	case signRule.MatchString(raw):
+		var match = signRule.FindStringSubmatch(raw)
+		rule.Kind = RuleInteger
+		if strings.ToLower(match[2]) == "float" {
+			rule.Kind = RuleNumber
+		}
+		rule.Value = match[1]
+	case formats[key] != "":
+		rule.Kind = RuleFormat
+		rule.Value = formats[key]
+	}
+	return rule
+}
+
+// Has reports whether a rule of the given kind exists.
+func (r ValidationRules) Has(kind RuleKind) bool {
+	for _, rule := range r {
+		if rule.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the first rule of the given kind.
+func (r ValidationRules) Find(kind RuleKind) (ValidationRule, bool) {
+	for _, rule := range r {
+		if rule.Kind == kind {
+			return rule, true
+		}
+	}
+	return ValidationRule{}, false
+}
+
+// Required reports whether the field must be present.
+func (r ValidationRules) Required() bool {
+	return r.Has(RuleRequired)
+}
+
+// LengthRange returns the minimum and maximum string length, -1 meaning unbounded.
+func (r ValidationRules) LengthRange() (int, int) {
+	var min, max = -1, -1
+	for _, rule := range r {
+		switch rule.Kind {
+		case RuleMinLength:
+			if n := int(rule.Number); n > min {
+				min = n
+			}
+		case RuleMaxLength:
+			if n := int(rule.Number); max == -1 || n < max {
+				max = n
+			}
+		case RuleLength:
+			min, max = int(rule.Number), int(rule.Number)
+		case RulePassword:
+			var n = 6
+			if rule.Value == "hard" {
+				n = 8
+			}
+			if n > min {
+				min = n
+			}
+		}
+	}
+	return min, max
+}
+
+// Format returns the string format required by the rules, if any.
+func (r ValidationRules) Format() string {
+	if rule, ok := r.Find(RuleFormat); ok {
+		return rule.Value
+	}
+	return ""
+}
+
+// Pattern returns the regular expression values must match, if any.
+func (r ValidationRules) Pattern() string {
+	if rule, ok := r.Find(RulePattern); ok {
+		return rule.Value
+	}
+	if rule, ok := r.Find(RuleFormat); ok {
+		return patterns[rule.Value]
+	}
+	return ""
+}
+
+// Text returns a human readable description of the rule.
+func (rule ValidationRule) Text() string {
+	var n = strconv.FormatFloat(rule.Number, 'f', -1, 64)
+	switch rule.Kind {
+	case RuleRequired:
+		return "required"
+	case RuleMinLength:
+		return "at least " + n + " characters"
+	case RuleMaxLength:
+		return "at most " + n + " characters"
+	case RuleLength:
+		return "exactly " + n + " characters"
+	case RuleNotLength:
+		return "length must not be " + n
+	case RuleMinimum:
+		if rule.Exclusive {
+			return "greater than " + n
+		}
+		return "greater than or equal to " + n
+	case RuleMaximum:
+		if rule.Exclusive {
+			return "less than " + n
+		}
+		return "less than or equal to " + n
+	case RuleEqual:
+		return "equal to " + n
+	case RuleNotEqual:
+		return "not equal to " + n
+	case RulePattern:
+		return "must match `" + rule.Value + "`"
+	case RuleFormat:
+		return "valid " + rule.Value
+	case RuleOneOf:
+		if len(rule.Values) > 0 {
+			return "one of " + strings.Join(rule.Values, ", ")
+		}
+		return "one of the enum values"
+	case RuleUnique:
+		if rule.Value != "" {
+			return "unique together with " + rule.Value
+		}
+		return "unique"
+	case RuleForeignKey:
+		return "must reference an existing record"
+	case RuleBefore:
+		return "before " + rule.Value
+	case RuleAfter:
+		return "after " + rule.Value
+	case RulePassword:
+		return fmt.Sprintf("password of %s strength", rule.Value)
+	case RuleInteger, RuleNumber:
+		var sign = ""
+		switch rule.Value {
+		case "+":
+			sign = "positive "
+		case "-":
+			sign = "negative "
+		}
+		if rule.Kind == RuleInteger {
+			return sign + "integer"
+		}
+		return sign + "number"
+	}
+	return rule.Raw
+}
+
+// Text returns a human readable description of all rules.
+func (r ValidationRules) Text() string {
+	var texts []string
+	for _, rule := range r {
+		texts = append(texts, rule.Text())
+	}
+	return strings.Join(texts, ", ")
+}
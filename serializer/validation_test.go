@@ -0,0 +1,74 @@
+package serializer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseValidation(t *testing.T) {
+	var tests = []struct {
+		tag  string
+		want ValidationRules
+	}{
+		{"", nil},
+		{"required", ValidationRules{{Kind: RuleRequired, Raw: "required"}}},
+		{"len>=3", ValidationRules{{Kind: RuleMinLength, Raw: "len>=3", Number: 3}}},
+		{"len>3", ValidationRules{{Kind: RuleMinLength, Raw: "len>3", Number: 4}}},
+		{"len<10", ValidationRules{{Kind: RuleMaxLength, Raw: "len<10", Number: 9}}},
+		{"len==5", ValidationRules{{Kind: RuleLength, Raw: "len==5", Number: 5}}},
+		{"len!=2", ValidationRules{{Kind: RuleNotLength, Raw: "len!=2", Number: 2}}},
+		{">0", ValidationRules{{Kind: RuleMinimum, Raw: ">0", Exclusive: true}}},
+		{"<=1.5", ValidationRules{{Kind: RuleMaximum, Raw: "<=1.5", Number: 1.5}}},
+		{"!=-1", ValidationRules{{Kind: RuleNotEqual, Raw: "!=-1", Number: -1}}},
+		{"email", ValidationRules{{Kind: RuleFormat, Raw: "email", Value: "email"}}},
+		{"alpha_numeric", ValidationRules{{Kind: RuleFormat, Raw: "alpha_numeric", Value: "alphanumeric"}}},
+		{"+int", ValidationRules{{Kind: RuleInteger, Raw: "+int", Value: "+"}}},
+		{"float", ValidationRules{{Kind: RuleNumber, Raw: "float"}}},
+		{"unique:tenant_id", ValidationRules{{Kind: RuleUnique, Raw: "unique:tenant_id", Value: "tenant_id"}}},
+		{"after(starts_at)", ValidationRules{{Kind: RuleAfter, Raw: "after(starts_at)", Value: "starts_at"}}},
+		{"password(Hard)", ValidationRules{{Kind: RulePassword, Raw: "password(Hard)", Value: "hard"}}},
+		{"something", ValidationRules{{Kind: RuleCustom, Raw: "something"}}},
+		{`regex(^a\,b$)`, ValidationRules{{Kind: RulePattern, Raw: `regex(^a\,b$)`, Value: `^a\,b$`}}},
+		{"required, len<=20 ,fk", ValidationRules{
+			{Kind: RuleRequired, Raw: "required"},
+			{Kind: RuleMaxLength, Raw: "len<=20", Number: 20},
+			{Kind: RuleForeignKey, Raw: "fk"},
+		}},
+	}
+	for _, test := range tests {
+		if got := ParseValidation(test.tag); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseValidation(%q) = %+v, want %+v", test.tag, got, test.want)
+		}
+	}
+}
+
+func TestValidationRulesLengthRange(t *testing.T) {
+	var tests = []struct {
+		tag      string
+		min, max int
+	}{
+		{"", -1, -1},
+		{"len>=3,len<=10", 3, 10},
+		{"len<=10,len<=5", -1, 5},
+		{"len==4", 4, 4},
+		{"password(hard)", 8, -1},
+	}
+	for _, test := range tests {
+		min, max := ParseValidation(test.tag).LengthRange()
+		if min != test.min || max != test.max {
+			t.Errorf("LengthRange(%q) = %d, %d, want %d, %d", test.tag, min, max, test.min, test.max)
+		}
+	}
+}
+
+func TestValidationRulesPattern(t *testing.T) {
+	if got := ParseValidation("slug").Pattern(); got != patterns["slug"] {
+		t.Errorf("Pattern(slug) = %q", got)
+	}
+	if got := ParseValidation(`regex(^\d+$),slug`).Pattern(); got != `^\d+$` {
+		t.Errorf("explicit regex must win over the format pattern, got %q", got)
+	}
+	if got := ParseValidation("required,len>=3").Text(); got != "required, at least 3 characters" {
+		t.Errorf("Text() = %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package serializer
+
+import (
+	"gorm.io/gorm/schema"
+	"strings"
+)
+
+// Visibility tells in which payloads a field appears. It combines gorm permissions
+// (`gorm:"<-:create"`, `gorm:"->"`, ...) with the json tag, where `json:"-"` hides the field,
+// omit_encode keeps it out of responses and omit_decode keeps it out of request bodies.
+type Visibility struct {
+	JsonName  string `json:"json_name"`
+	Readable  bool   `json:"readable"`
+	Creatable bool   `json:"creatable"`
+	Updatable bool   `json:"updatable"`
+	Hidden    bool   `json:"hidden"`
+}
+
+// NewVisibility computes the visibility of a gorm field.
+func NewVisibility(field *schema.Field) Visibility {
+	var tag = field.Tag.Get("json")
+	var options = strings.Split(tag, ",")
+	var visibility = Visibility{
+		JsonName:  options[0],
+		Readable:  field.Readable,
+		Creatable: field.Creatable,
+		Updatable: field.Updatable,
+	}
+	if tag == "-" {
+		return Visibility{JsonName: field.Name, Hidden: true}
+	}
+	if visibility.JsonName == "" {
+		visibility.JsonName = field.Name
+	}
+	for _, option := range options[1:] {
+		switch strings.TrimSpace(option) {
+		case "omit_encode":
+			visibility.Readable = false
+		case "omit_decode":
+			visibility.Creatable = false
+			visibility.Updatable = false
+		}
+	}
+	if !visibility.Readable && !visibility.Creatable && !visibility.Updatable {
+		visibility.Hidden = true
+	}
+	return visibility
+}
+
+// InResponse reports whether the field is returned by the API.
+func (f Field) InResponse() bool {
+	return !f.Visibility.Hidden && f.Visibility.Readable
+}
+
+// InCreate reports whether the field is accepted when creating a record.
+func (f Field) InCreate() bool {
+	return !f.Visibility.Hidden && f.Visibility.Creatable && !f.AutoIncrement
+}
+
+// InUpdate reports whether the field is accepted when updating a record.
+func (f Field) InUpdate() bool {
+	return !f.Visibility.Hidden && f.Visibility.Updatable && !f.PrimaryKey
+}
+
+// InRequest reports whether the field is accepted by any request body.
+func (f Field) InRequest() bool {
+	return f.InCreate() || f.InUpdate()
+}
+
+// ReadOnly reports whether the field is returned but never accepted.
+func (f Field) ReadOnly() bool {
+	return f.InResponse() && !f.InRequest()
+}
+
+// WriteOnly reports whether the field is accepted but never returned.
+func (f Field) WriteOnly() bool {
+	return !f.InResponse() && f.InRequest()
+}